@@ -13,6 +13,7 @@ import (
 	"github.com/marchuknikolay/rss-parser/internal/fetcher"
 	"github.com/marchuknikolay/rss-parser/internal/parser"
 	"github.com/marchuknikolay/rss-parser/internal/repository"
+	"github.com/marchuknikolay/rss-parser/internal/scheduler"
 	"github.com/marchuknikolay/rss-parser/internal/server"
 	"github.com/marchuknikolay/rss-parser/internal/server/handlers"
 	"github.com/marchuknikolay/rss-parser/internal/service"
@@ -34,11 +35,18 @@ func main() {
 	}
 
 	svc := service.New(
-		fetcher.New(http.DefaultClient),
+		fetcher.NewFromConfig(http.DefaultClient, cfg.Fetcher),
 		parser.Parser{},
 		st,
 		repository.ChannelRepositoryFactory{},
-		repository.ItemRepositoryFactory{})
+		repository.ItemRepositoryFactory{},
+		service.WithRefreshInterval(cfg.Scheduler.Interval),
+		service.WithMaxDecodedSize(cfg.Fetcher.MaxDecodedSize))
+
+	sch, err := scheduler.New(svc, cfg.Scheduler.Interval)
+	if err != nil {
+		log.Fatalf("Failed creating the scheduler: %v", err)
+	}
 
 	echo, err := handlers.New(svc).InitRoutes()
 	if err != nil {
@@ -55,6 +63,10 @@ func main() {
 
 	log.Printf("The server is running on port %v", cfg.Server.Port)
 
+	sch.Start()
+
+	log.Printf("Feeds are refreshed every %v", cfg.Scheduler.Interval)
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGTERM, syscall.SIGINT)
 	<-quit
@@ -68,6 +80,10 @@ func main() {
 		log.Printf("Failed shutting down the server: %v", err)
 	}
 
+	if err := sch.Shutdown(ctx); err != nil {
+		log.Printf("Failed shutting down the scheduler: %v", err)
+	}
+
 	st.Close()
 
 	log.Println("The server stopped gracefully")
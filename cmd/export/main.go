@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/config"
+	"github.com/marchuknikolay/rss-parser/internal/opml"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
+	"github.com/marchuknikolay/rss-parser/internal/storage"
+)
+
+const maxArgsCount = 2
+
+// export writes every stored channel as an OPML 2.0 document to the file
+// given as the only argument, or to stdout.
+func main() {
+	if actualArgsCount := len(os.Args); actualArgsCount > maxArgsCount {
+		log.Fatalf("Maximum args count is %v, but actual is %v\n", maxArgsCount, actualArgsCount)
+	}
+
+	cfg, err := config.New()
+	if err != nil {
+		log.Fatalf("Failed loading config: %v", err)
+	}
+
+	connString := fmt.Sprintf("postgres://%v:%v@%v:%v/%v",
+		cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.ContainerPort, cfg.DB.Name)
+
+	st, err := storage.New(connString)
+	if err != nil {
+		log.Fatalf("Failed creating a new database connection: %v", err)
+	}
+
+	channels, err := repository.ChannelRepositoryFactory{}.New(st).GetAll(context.Background())
+
+	st.Close()
+
+	if err != nil {
+		log.Fatalf("Failed getting channels: %v", err)
+	}
+
+	feeds := opml.FromChannels(channels)
+
+	if len(os.Args) == maxArgsCount {
+		err = writeFile(os.Args[1], feeds)
+	} else {
+		err = write(os.Stdout, feeds)
+	}
+
+	if err != nil {
+		log.Fatalf("Failed exporting channels: %v", err)
+	}
+
+	log.Printf("Exported %v feeds", len(feeds))
+}
+
+func writeFile(path string, feeds []opml.Feed) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f, feeds); err != nil {
+		_ = f.Close()
+
+		return err
+	}
+
+	return f.Close()
+}
+
+func write(w io.Writer, feeds []opml.Feed) error {
+	return opml.Write(w, opml.ExportTitle, feeds, time.Now())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/marchuknikolay/rss-parser/internal/config"
+	"github.com/marchuknikolay/rss-parser/internal/fetcher"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+	"github.com/marchuknikolay/rss-parser/internal/service"
+)
+
+const argsCount = 2
+
+// validate fetches and parses the feed at the URL given as the only argument
+// without importing it, and writes the validation report as JSON to stdout.
+// It exits with status 1 if the feed has errors.
+func main() {
+	if actualArgsCount := len(os.Args); actualArgsCount != argsCount {
+		log.Fatalf("Args count is %v, but actual is %v\n", argsCount, actualArgsCount)
+	}
+
+	cfg, err := config.NewFetcher()
+	if err != nil {
+		log.Fatalf("Failed loading config: %v", err)
+	}
+
+	f := fetcher.NewFromConfig(http.DefaultClient, *cfg)
+
+	report, err := service.ValidateFeed(context.Background(), f, parser.Parser{}, cfg.MaxDecodedSize, os.Args[1])
+	if err != nil {
+		log.Fatalf("Failed fetching feed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(report); err != nil {
+		log.Fatalf("Failed writing report: %v", err)
+	}
+
+	if !report.Valid {
+		os.Exit(1)
+	}
+}
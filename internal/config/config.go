@@ -22,9 +22,24 @@ type ServerConfig struct {
 	ReadHeaderTimeout time.Duration `env:"SERVER_READ_HEADER_TIMEOUT, required"`
 }
 
+type FetcherConfig struct {
+	MaxAttempts    int           `env:"FETCHER_MAX_ATTEMPTS, required"`
+	RetryBaseDelay time.Duration `env:"FETCHER_RETRY_BASE_DELAY, required"`
+	RetryMaxDelay  time.Duration `env:"FETCHER_RETRY_MAX_DELAY, required"`
+	RetryJitter    float64       `env:"FETCHER_RETRY_JITTER, required"`
+	MaxBodySize    int64         `env:"FETCHER_MAX_BODY_SIZE, required"`
+	MaxDecodedSize int64         `env:"FETCHER_MAX_DECODED_SIZE, required"`
+}
+
+type SchedulerConfig struct {
+	Interval time.Duration `env:"SCHEDULER_INTERVAL, required"`
+}
+
 type Config struct {
-	DB     DBConfig
-	Server ServerConfig
+	DB        DBConfig
+	Server    ServerConfig
+	Fetcher   FetcherConfig
+	Scheduler SchedulerConfig
 }
 
 func New() (*Config, error) {
@@ -36,3 +51,15 @@ func New() (*Config, error) {
 
 	return &config, nil
 }
+
+// NewFetcher loads only the fetcher settings, for commands that fetch feeds
+// without a database or a server.
+func NewFetcher() (*FetcherConfig, error) {
+	var config FetcherConfig
+
+	if err := envdecode.StrictDecode(&config); err != nil {
+		return nil, fmt.Errorf("failed decoding .env file: %w", err)
+	}
+
+	return &config, nil
+}
@@ -20,6 +20,15 @@ func TestNew(t *testing.T) {
 
 			serverPort = 4321
 			timeout    = 5 * time.Second
+
+			maxAttempts    = 3
+			retryBaseDelay = time.Second
+			retryMaxDelay  = 30 * time.Second
+			retryJitter    = 0.2
+			maxBodySize    = 1 << 20
+			maxDecodedSize = 8 << 20
+
+			schedulerInterval = 30 * time.Minute
 		)
 
 		t.Cleanup(func() {
@@ -37,6 +46,15 @@ func TestNew(t *testing.T) {
 		t.Setenv("SERVER_SHUTDOWN_TIMEOUT", timeout.String())
 		t.Setenv("SERVER_READ_HEADER_TIMEOUT", timeout.String())
 
+		t.Setenv("FETCHER_MAX_ATTEMPTS", strconv.Itoa(maxAttempts))
+		t.Setenv("FETCHER_RETRY_BASE_DELAY", retryBaseDelay.String())
+		t.Setenv("FETCHER_RETRY_MAX_DELAY", retryMaxDelay.String())
+		t.Setenv("FETCHER_RETRY_JITTER", strconv.FormatFloat(retryJitter, 'f', -1, 64))
+		t.Setenv("FETCHER_MAX_BODY_SIZE", strconv.Itoa(maxBodySize))
+		t.Setenv("FETCHER_MAX_DECODED_SIZE", strconv.Itoa(maxDecodedSize))
+
+		t.Setenv("SCHEDULER_INTERVAL", schedulerInterval.String())
+
 		config, err := New()
 
 		require.NoError(t, err)
@@ -51,6 +69,15 @@ func TestNew(t *testing.T) {
 		require.Equal(t, serverPort, config.Server.Port)
 		require.Equal(t, timeout, config.Server.ShutdownTimeout)
 		require.Equal(t, timeout, config.Server.ReadHeaderTimeout)
+
+		require.Equal(t, maxAttempts, config.Fetcher.MaxAttempts)
+		require.Equal(t, retryBaseDelay, config.Fetcher.RetryBaseDelay)
+		require.Equal(t, retryMaxDelay, config.Fetcher.RetryMaxDelay)
+		require.InDelta(t, retryJitter, config.Fetcher.RetryJitter, 1e-9)
+		require.Equal(t, int64(maxBodySize), config.Fetcher.MaxBodySize)
+		require.Equal(t, int64(maxDecodedSize), config.Fetcher.MaxDecodedSize)
+
+		require.Equal(t, schedulerInterval, config.Scheduler.Interval)
 	})
 
 	t.Run("MissingEnvVariables", func(t *testing.T) {
@@ -62,3 +89,41 @@ func TestNew(t *testing.T) {
 		require.Nil(t, config)
 	})
 }
+
+func TestNewFetcher(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		t.Cleanup(func() {
+			os.Clearenv()
+		})
+
+		os.Clearenv()
+
+		t.Setenv("FETCHER_MAX_ATTEMPTS", "3")
+		t.Setenv("FETCHER_RETRY_BASE_DELAY", "1s")
+		t.Setenv("FETCHER_RETRY_MAX_DELAY", "30s")
+		t.Setenv("FETCHER_RETRY_JITTER", "0.2")
+		t.Setenv("FETCHER_MAX_BODY_SIZE", "1024")
+		t.Setenv("FETCHER_MAX_DECODED_SIZE", "2048")
+
+		config, err := NewFetcher()
+
+		require.NoError(t, err)
+		require.Equal(t, &FetcherConfig{
+			MaxAttempts:    3,
+			RetryBaseDelay: time.Second,
+			RetryMaxDelay:  30 * time.Second,
+			RetryJitter:    0.2,
+			MaxBodySize:    1024,
+			MaxDecodedSize: 2048,
+		}, config)
+	})
+
+	t.Run("MissingEnvVariables", func(t *testing.T) {
+		os.Clearenv()
+
+		config, err := NewFetcher()
+
+		require.Error(t, err)
+		require.Nil(t, config)
+	})
+}
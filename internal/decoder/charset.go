@@ -0,0 +1,88 @@
+package decoder
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+var ErrUnsupportedCharset = errors.New("unsupported charset")
+
+// CharsetReader is meant for xml.Decoder.CharsetReader, which is consulted
+// for every encoding declared in an XML prolog other than UTF-8. It converts
+// legacy encodings such as windows-1251, KOI8-R or ISO-8859-1 to UTF-8, using
+// the same labels as browsers do.
+func CharsetReader(label string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedCharset, label)
+	}
+
+	return transform.NewReader(input, enc.NewDecoder()), nil
+}
+
+// DeclaredCharset returns the encoding named in the XML declaration of the
+// document, or "" if it doesn't declare one.
+func DeclaredCharset(bs []byte) string {
+	d := xml.NewDecoder(bytes.NewReader(bytes.TrimPrefix(bs, []byte("\xEF\xBB\xBF"))))
+	// The declaration is only read, so the content is left as it is.
+	d.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) {
+		return input, nil
+	}
+
+	for {
+		token, err := d.RawToken()
+		if err != nil {
+			return ""
+		}
+
+		switch token := token.(type) {
+		case xml.CharData:
+			if len(bytes.TrimSpace(token)) == 0 {
+				continue
+			}
+		case xml.ProcInst:
+			if token.Target == "xml" {
+				return pseudoAttr(string(token.Inst), "encoding")
+			}
+		}
+
+		return ""
+	}
+}
+
+// pseudoAttr returns the value of a pseudo-attribute of a processing
+// instruction, such as encoding="UTF-8". Spaces are allowed around "=".
+func pseudoAttr(inst, name string) string {
+	for {
+		i := strings.Index(inst, name)
+		if i < 0 {
+			return ""
+		}
+
+		inst = inst[i+len(name):]
+
+		value := strings.TrimLeft(inst, " \t\r\n")
+		if !strings.HasPrefix(value, "=") {
+			continue
+		}
+
+		value = strings.TrimLeft(value[1:], " \t\r\n")
+		if value == "" || (value[0] != '"' && value[0] != '\'') {
+			return ""
+		}
+
+		n := strings.IndexByte(value[1:], value[0])
+		if n < 0 {
+			return ""
+		}
+
+		return value[1 : n+1]
+	}
+}
@@ -0,0 +1,165 @@
+package decoder
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+var (
+	ErrUnsupportedEncoding = errors.New("unsupported content encoding")
+	ErrDecodedTooLarge     = errors.New("decoded content is too large")
+)
+
+// gzipMagic starts every gzip stream.
+const gzipMagic = "\x1f\x8b"
+
+// Decompress undoes the Content-Encoding of a fetched body. Feeds published
+// as raw .gz files, which servers send without any Content-Encoding, are
+// recognized by their signature. maxSize protects against compression bombs,
+// since a few megabytes of gzip can expand to gigabytes: content that decodes
+// to more bytes fails with ErrDecodedTooLarge. A maxSize of 0 or less
+// disables the limit.
+func Decompress(body []byte, contentEncoding string, maxSize int64) ([]byte, error) {
+	r, err := NewReader(bytes.NewReader(body), contentEncoding, maxSize)
+	if err != nil {
+		return nil, err
+	}
+
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return bs, nil
+}
+
+// NewReader is Decompress for a body that is read as it arrives. Corrupt
+// content and ErrDecodedTooLarge are reported by Read then.
+func NewReader(body io.Reader, contentEncoding string, maxSize int64) (io.Reader, error) {
+	var (
+		r       = body
+		decoded bool
+		err     error
+	)
+
+	// Encodings are listed in the order they were applied.
+	encodings := strings.Split(contentEncoding, ",")
+
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		if encoding == "" || encoding == "identity" {
+			continue
+		}
+
+		if r, err = decompress(r, encoding); err != nil {
+			return nil, err
+		}
+
+		decoded = true
+	}
+
+	br := bufio.NewReader(r)
+	r = br
+
+	if magic, _ := br.Peek(len(gzipMagic)); string(magic) == gzipMagic {
+		if r, err = decompress(br, "gzip"); err != nil {
+			return nil, err
+		}
+
+		decoded = true
+	}
+
+	// An undecoded body is already limited by the fetcher.
+	if decoded && maxSize > 0 {
+		r = &limitedReader{r: r, left: maxSize, maxSize: maxSize}
+	}
+
+	return r, nil
+}
+
+func decompress(body io.Reader, encoding string) (io.Reader, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+
+	switch encoding {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(body)
+	case "deflate":
+		// "deflate" means zlib-wrapped data, but some servers send a raw
+		// deflate stream instead.
+		br := bufio.NewReader(body)
+		if header, _ := br.Peek(2); isZlibHeader(header) {
+			r, err = zlib.NewReader(br)
+		} else {
+			r = flate.NewReader(br)
+		}
+	case "br":
+		r = brotli.NewReader(body)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedEncoding, encoding)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed decoding %v content: %w", encoding, err)
+	}
+
+	return &decodingReader{r: r, encoding: encoding}, nil
+}
+
+// isZlibHeader checks the two header bytes of a zlib stream: the deflate
+// method and the checksum over them.
+func isZlibHeader(header []byte) bool {
+	return len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0
+}
+
+// decodingReader names the encoding in the errors of a decompressor.
+type decodingReader struct {
+	r        io.Reader
+	encoding string
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("failed decoding %v content: %w", d.encoding, err)
+	}
+
+	return n, err
+}
+
+// limitedReader fails with ErrDecodedTooLarge once more than maxSize bytes
+// are read.
+type limitedReader struct {
+	r       io.Reader
+	left    int64
+	maxSize int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.left+1 {
+		p = p[:l.left+1]
+	}
+
+	n, err := l.r.Read(p)
+	if int64(n) > l.left {
+		n = int(l.left)
+		l.left = 0
+
+		return n, fmt.Errorf("%w: more than %v bytes", ErrDecodedTooLarge, l.maxSize)
+	}
+
+	l.left -= int64(n)
+
+	return n, err
+}
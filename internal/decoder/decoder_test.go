@@ -0,0 +1,185 @@
+package decoder
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/require"
+)
+
+const content = `<?xml version="1.0" encoding="UTF-8"?><rss><channel><title>Channel 1</title></channel></rss>`
+
+func TestDecompress(t *testing.T) {
+	tests := []struct {
+		name            string
+		body            []byte
+		contentEncoding string
+	}{
+		{name: "Identity", body: []byte(content), contentEncoding: ""},
+		{name: "Gzip", body: compress(t, gzipWriter, content), contentEncoding: "gzip"},
+		{name: "XGzip", body: compress(t, gzipWriter, content), contentEncoding: "x-gzip"},
+		{name: "Zlib", body: compress(t, zlibWriter, content), contentEncoding: "deflate"},
+		{name: "RawDeflate", body: compress(t, flateWriter, content), contentEncoding: "deflate"},
+		{name: "Brotli", body: compress(t, brotliWriter, content), contentEncoding: "br"},
+		{name: "RawGzipFile", body: compress(t, gzipWriter, content), contentEncoding: ""},
+		{
+			name:            "Chained",
+			body:            compress(t, brotliWriter, string(compress(t, zlibWriter, content))),
+			contentEncoding: "deflate, br",
+		},
+		{
+			name:            "GzipFileServedWithGzipEncoding",
+			body:            compress(t, gzipWriter, string(compress(t, gzipWriter, content))),
+			contentEncoding: "gzip",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := Decompress(tt.body, tt.contentEncoding, 1<<10)
+
+			require.NoError(t, err)
+			require.Equal(t, content, string(actual))
+		})
+	}
+
+	t.Run("UnsupportedEncoding", func(t *testing.T) {
+		_, err := Decompress([]byte(content), "compress", 0)
+
+		require.ErrorIs(t, err, ErrUnsupportedEncoding)
+	})
+
+	t.Run("CorruptGzip", func(t *testing.T) {
+		_, err := Decompress([]byte(content), "gzip", 0)
+
+		require.Error(t, err)
+	})
+
+	t.Run("DecodedTooLarge", func(t *testing.T) {
+		_, err := Decompress(compress(t, gzipWriter, content), "gzip", int64(len(content)-1))
+
+		require.ErrorIs(t, err, ErrDecodedTooLarge)
+	})
+}
+
+func TestNewReader(t *testing.T) {
+	t.Run("Gzip", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(compress(t, gzipWriter, content)), "gzip", 1<<10)
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.Equal(t, content, string(bs))
+	})
+
+	t.Run("DecodedTooLarge", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(compress(t, brotliWriter, content)), "br", 10)
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(r)
+
+		require.ErrorIs(t, err, ErrDecodedTooLarge)
+		require.Equal(t, content[:10], string(bs))
+	})
+
+	t.Run("Identity", func(t *testing.T) {
+		r, err := NewReader(strings.NewReader(content), "", 10)
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.Equal(t, content, string(bs))
+	})
+}
+
+func TestCharsetReader(t *testing.T) {
+	t.Run("Windows1251", func(t *testing.T) {
+		// "Привет" in windows-1251
+		r, err := CharsetReader("windows-1251", strings.NewReader("\xcf\xf0\xe8\xe2\xe5\xf2"))
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.Equal(t, "Привет", string(bs))
+	})
+
+	t.Run("Latin1", func(t *testing.T) {
+		r, err := CharsetReader("ISO-8859-1", strings.NewReader("caf\xe9"))
+		require.NoError(t, err)
+
+		bs, err := io.ReadAll(r)
+
+		require.NoError(t, err)
+		require.Equal(t, "café", string(bs))
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := CharsetReader("x-unknown", strings.NewReader(""))
+
+		require.ErrorIs(t, err, ErrUnsupportedCharset)
+	})
+}
+
+func TestDeclaredCharset(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{"DoubleQuotes", `<?xml version="1.0" encoding="windows-1251"?><rss/>`, "windows-1251"},
+		{"SingleQuotes", `<?xml version='1.0' encoding='ISO-8859-1'?><rss/>`, "ISO-8859-1"},
+		{"SpacesAroundEquals", `<?xml version = "1.0" encoding = "KOI8-R" ?><rss/>`, "KOI8-R"},
+		{"LeadingWhitespace", "\n  " + `<?xml version="1.0" encoding="windows-1251"?><rss/>`, "windows-1251"},
+		{"ByteOrderMark", "\xEF\xBB\xBF" + content, "UTF-8"},
+		{"NoEncoding", `<?xml version="1.0"?><rss/>`, ""},
+		{"NoDeclaration", `<rss/>`, ""},
+		{"Json", `{"version": "https://jsonfeed.org/version/1.1"}`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, DeclaredCharset([]byte(tt.document)))
+		})
+	}
+}
+
+func gzipWriter(w io.Writer) io.WriteCloser {
+	return gzip.NewWriter(w)
+}
+
+func zlibWriter(w io.Writer) io.WriteCloser {
+	return zlib.NewWriter(w)
+}
+
+func flateWriter(w io.Writer) io.WriteCloser {
+	fw, _ := flate.NewWriter(w, flate.DefaultCompression) //nolint:errcheck
+
+	return fw
+}
+
+func brotliWriter(w io.Writer) io.WriteCloser {
+	return brotli.NewWriter(w)
+}
+
+func compress(t *testing.T, newWriter func(io.Writer) io.WriteCloser, s string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := newWriter(&buf)
+
+	_, err := io.WriteString(w, s)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
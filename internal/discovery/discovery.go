@@ -0,0 +1,165 @@
+package discovery
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const utf8Bom = "\xEF\xBB\xBF"
+
+// feedTypes are the link types that announce a feed.
+func feedTypes() []string {
+	return []string{"application/rss+xml", "application/atom+xml", "application/feed+json"}
+}
+
+// wellKnownPaths are tried when a page doesn't link to its feed, in the order
+// of how common they are.
+func wellKnownPaths() []string {
+	return []string{"/feed", "/rss.xml", "/atom.xml", "/feed.xml", "/index.xml", "/rss"}
+}
+
+// IsHtml reports whether a fetched document is an HTML page rather than a
+// feed. The content is sniffed instead of trusting Content-Type, since feeds
+// are often served as text/html.
+func IsHtml(bs []byte) bool {
+	rest := bytes.TrimSpace(bytes.TrimPrefix(bs, []byte(utf8Bom)))
+
+	// XHTML pages may start with an XML declaration or comments.
+	for {
+		var end string
+
+		switch {
+		case bytes.HasPrefix(rest, []byte("<?")):
+			end = "?>"
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			end = "-->"
+		}
+
+		if end == "" {
+			break
+		}
+
+		i := bytes.Index(rest, []byte(end))
+		if i < 0 {
+			return false
+		}
+
+		rest = bytes.TrimSpace(rest[i+len(end):])
+	}
+
+	prefix := strings.ToLower(string(rest[:min(len(rest), len("<!doctype html"))]))
+
+	return strings.HasPrefix(prefix, "<!doctype html") || strings.HasPrefix(prefix, "<html")
+}
+
+// FeedUrls lists the URLs that may hold the feed of the page fetched from
+// pageUrl: first the feeds announced with <link rel="alternate">, then the
+// well-known feed paths of the site. Relative URLs are resolved against the
+// page, or its <base> if it has one.
+func FeedUrls(page []byte, pageUrl string) ([]string, error) {
+	base, err := url.Parse(pageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing page url %v: %w", pageUrl, err)
+	}
+
+	var (
+		urls []string
+		seen = make(map[string]struct{})
+	)
+
+	add := func(ref string) {
+		u, err := base.Parse(strings.TrimSpace(ref))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return
+		}
+
+		u.Fragment = ""
+
+		if _, ok := seen[u.String()]; ok {
+			return
+		}
+
+		seen[u.String()] = struct{}{}
+		urls = append(urls, u.String())
+	}
+
+	z := html.NewTokenizer(bytes.NewReader(page))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		tok := z.Token()
+
+		switch tok.DataAtom {
+		case atom.Base:
+			if href := attr(tok, "href"); href != "" {
+				if u, err := base.Parse(href); err == nil {
+					base = u
+				}
+			}
+		case atom.Link:
+			if isFeedLink(tok) {
+				add(attr(tok, "href"))
+			}
+		}
+	}
+
+	for _, path := range wellKnownPaths() {
+		add(path)
+	}
+
+	return urls, nil
+}
+
+func isFeedLink(tok html.Token) bool {
+	if attr(tok, "href") == "" {
+		return false
+	}
+
+	isAlternate := false
+
+	for _, rel := range strings.Fields(attr(tok, "rel")) {
+		if strings.EqualFold(rel, "alternate") {
+			isAlternate = true
+		}
+	}
+
+	if !isAlternate {
+		return false
+	}
+
+	linkType := strings.ToLower(strings.TrimSpace(attr(tok, "type")))
+	if i := strings.IndexByte(linkType, ';'); i >= 0 {
+		linkType = strings.TrimSpace(linkType[:i])
+	}
+
+	for _, feedType := range feedTypes() {
+		if linkType == feedType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attr(tok html.Token, name string) string {
+	for _, a := range tok.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+
+	return ""
+}
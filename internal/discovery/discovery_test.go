@@ -0,0 +1,94 @@
+package discovery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsHtml(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want bool
+	}{
+		{"Html5", "\n<!DOCTYPE html>\n<html lang=\"en\"></html>", true},
+		{"NoDoctype", "<HTML><head></head></HTML>", true},
+		{"Xhtml", `<?xml version="1.0"?><!-- page --><!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Strict//EN">`, true},
+		{"Rss", `<?xml version="1.0"?><rss version="2.0"></rss>`, false},
+		{"Atom", `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`, false},
+		{"Json", `{"version": "https://jsonfeed.org/version/1.1"}`, false},
+		{"UnterminatedComment", "<!-- <html>", false},
+		{"Empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IsHtml([]byte(tt.doc)))
+		})
+	}
+}
+
+func TestFeedUrls(t *testing.T) {
+	t.Run("AlternateLinks", func(t *testing.T) {
+		const page = `<!DOCTYPE html>
+			<html>
+			<head>
+				<link rel="stylesheet" href="/style.css">
+				<link rel="alternate" type="application/rss+xml" title="Posts" href="/blog/rss.xml">
+				<link rel="alternate" type="application/atom+xml" href="atom.xml" />
+				<link rel="Alternate" type="application/feed+json; charset=utf-8" href="https://cdn.example.com/feed.json">
+				<link rel="alternate" type="text/html" hreflang="de" href="/de/">
+				<link rel="alternate" type="application/rss+xml" href="/blog/rss.xml#duplicate">
+			</head>
+			<body></body>
+			</html>`
+
+		urls, err := FeedUrls([]byte(page), "https://example.com/blog/post.html")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"https://example.com/blog/rss.xml",
+			"https://example.com/blog/atom.xml",
+			"https://cdn.example.com/feed.json",
+			"https://example.com/feed",
+			"https://example.com/rss.xml",
+			"https://example.com/atom.xml",
+			"https://example.com/feed.xml",
+			"https://example.com/index.xml",
+			"https://example.com/rss",
+		}, urls)
+	})
+
+	t.Run("BaseElement", func(t *testing.T) {
+		const page = `<html><head>
+			<base href="https://static.example.org/site/">
+			<link rel="alternate" type="application/atom+xml" href="feed.atom">
+			</head></html>`
+
+		urls, err := FeedUrls([]byte(page), "https://example.com/")
+
+		require.NoError(t, err)
+		require.Equal(t, "https://static.example.org/site/feed.atom", urls[0])
+	})
+
+	t.Run("WellKnownPaths", func(t *testing.T) {
+		urls, err := FeedUrls([]byte("<html><body>No links</body></html>"), "http://example.com/about/")
+
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"http://example.com/feed",
+			"http://example.com/rss.xml",
+			"http://example.com/atom.xml",
+			"http://example.com/feed.xml",
+			"http://example.com/index.xml",
+			"http://example.com/rss",
+		}, urls)
+	})
+
+	t.Run("InvalidPageUrl", func(t *testing.T) {
+		_, err := FeedUrls(nil, "http://exa mple.com/%zz")
+
+		require.Error(t, err)
+	})
+}
@@ -2,44 +2,306 @@ package fetcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/config"
 )
 
 type HTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Validators identify the version of a feed returned by a previous fetch.
+type Validators struct {
+	Etag         string
+	LastModified string
+}
+
+type Response struct {
+	// Body is returned as sent, so it still has to be decoded according to
+	// ContentEncoding, see the decoder package.
+	Body            []byte
+	ContentEncoding string
+	Validators      Validators
+	// NotModified is set when the server answered 304 to a conditional
+	// request. Body is empty then.
+	NotModified bool
+}
+
+// StatusError is returned when the server answers with a status other than
+// 200 OK or 304 Not Modified.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %v", e.StatusCode)
+}
+
 type Fetcher struct {
-	client HTTPClient
+	client      HTTPClient
+	retryPolicy RetryPolicy
+	maxBodySize int64
+	sleep       func(ctx context.Context, d time.Duration) error
 }
 
-func New(c HTTPClient) Fetcher {
-	return Fetcher{client: c}
+type Option func(*Fetcher)
+
+// New creates a Fetcher that makes a single attempt per Fetch and reads at
+// most 10 MiB, unless configured otherwise with options.
+func New(c HTTPClient, opts ...Option) Fetcher {
+	f := Fetcher{
+		client:      c,
+		maxBodySize: defaultMaxBodySize,
+		sleep:       sleepContext,
+	}
+
+	for _, opt := range opts {
+		opt(&f)
+	}
+
+	return f
+}
+
+// NewFromConfig creates the Fetcher the commands use, with the retry policy
+// and the body size limit of the configuration.
+func NewFromConfig(c HTTPClient, cfg config.FetcherConfig) Fetcher {
+	return New(
+		c,
+		WithRetryPolicy(RetryPolicy{
+			MaxAttempts: cfg.MaxAttempts,
+			BaseDelay:   cfg.RetryBaseDelay,
+			MaxDelay:    cfg.RetryMaxDelay,
+			Jitter:      cfg.RetryJitter,
+		}),
+		WithMaxBodySize(cfg.MaxBodySize),
+	)
+}
+
+// Fetch downloads url. Non-empty validators make the request conditional, so
+// an unchanged feed is answered with NotModified instead of its content.
+func (f Fetcher) Fetch(ctx context.Context, url string, validators Validators) (Response, error) {
+	return retry(ctx, f, url, func() (Response, error) {
+		return f.fetch(ctx, url, validators)
+	})
+}
+
+// Open is Fetch for callers that consume the body as it arrives instead of
+// holding all of it in memory. Only the request is retried: once Body is
+// returned, failures surface from its Read, including ErrBodyTooLarge. Body
+// has to be closed, unless NotModified is set.
+func (f Fetcher) Open(ctx context.Context, url string, validators Validators) (Stream, error) {
+	resp, err := retry(ctx, f, url, func() (*http.Response, error) {
+		return f.open(ctx, url, validators)
+	})
+	if err != nil {
+		return Stream{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return Stream{Validators: validators, NotModified: true}, nil
+	}
+
+	if f.maxBodySize > 0 && resp.ContentLength > f.maxBodySize {
+		closeBody(resp)
+
+		return Stream{}, f.bodyTooLarge(url)
+	}
+
+	body := resp.Body
+	if f.maxBodySize > 0 {
+		body = &limitedBody{ReadCloser: resp.Body, left: f.maxBodySize, tooLarge: f.bodyTooLarge(url)}
+	}
+
+	return Stream{
+		Body:            body,
+		ContentEncoding: resp.Header.Get("Content-Encoding"),
+		Validators:      responseValidators(resp),
+	}, nil
+}
+
+// Stream is an opened response, see Open.
+type Stream struct {
+	Body            io.ReadCloser
+	ContentEncoding string
+	Validators      Validators
+	NotModified     bool
+}
+
+func retry[T any](ctx context.Context, f Fetcher, url string, attempt func() (T, error)) (T, error) {
+	var zero T
+
+	for n := 1; ; n++ {
+		result, err := attempt()
+
+		var retryable *retryableError
+		if err == nil || !errors.As(err, &retryable) || ctx.Err() != nil {
+			return result, err
+		}
+
+		delay, ok := f.retryPolicy.delay(n, retryable.retryAfter)
+		if !ok {
+			return zero, retryable.err
+		}
+
+		log.Printf("Retrying %v in %v after attempt %v failed: %v", url, delay, n, retryable.err)
+
+		if err := f.sleep(ctx, delay); err != nil {
+			return zero, fmt.Errorf("%w while waiting to retry: %w", err, retryable.err)
+		}
+	}
+}
+
+func (f Fetcher) fetch(ctx context.Context, url string, validators Validators) (Response, error) {
+	resp, err := f.open(ctx, url, validators)
+	if err != nil {
+		return Response{}, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		return Response{Validators: validators, NotModified: true}, nil
+	}
+
+	defer closeBody(resp)
+
+	bs, err := f.readBody(url, resp)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		Body:            bs,
+		ContentEncoding: resp.Header.Get("Content-Encoding"),
+		Validators:      responseValidators(resp),
+	}, nil
 }
 
-func (f Fetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+// open sends the request and checks the response before its body is read.
+// A 304 Not Modified response is returned with its body already closed.
+func (f Fetcher) open(ctx context.Context, url string, validators Validators) (*http.Response, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed creating a GET request for %v, %w", url, err)
 	}
 
+	// Setting Accept-Encoding stops net/http from decompressing gzip on its
+	// own, which lets the decoder handle every encoding in one place.
+	req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+
+	if validators.Etag != "" {
+		req.Header.Set("If-None-Match", validators.Etag)
+	}
+
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+
 	resp, err := f.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed getting data from %v, %w", url, err)
+		return nil, &retryableError{err: fmt.Errorf("failed getting data from %v, %w", url, err)}
 	}
 
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Printf("Failed to close response body: %v", err)
+	if resp.StatusCode == http.StatusOK {
+		if contentType := resp.Header.Get("Content-Type"); isUnsupportedContentType(contentType) {
+			closeBody(resp)
+
+			return nil, &RejectedError{Url: url, Err: ErrUnsupportedContentType, Detail: contentType}
 		}
-	}()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
+		return resp, nil
+	}
+
+	closeBody(resp)
+
+	if resp.StatusCode == http.StatusNotModified {
+		return resp, nil
+	}
+
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+
+	if isRetryableStatus(resp.StatusCode) {
+		return nil, &retryableError{
+			err:        statusErr,
+			retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+
+	return nil, statusErr
+}
+
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		log.Printf("Failed to close response body: %v", err)
+	}
+}
+
+func responseValidators(resp *http.Response) Validators {
+	return Validators{
+		Etag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
 	}
+}
+
+func (f Fetcher) readBody(url string, resp *http.Response) ([]byte, error) {
+	var body io.Reader = resp.Body
+
+	if f.maxBodySize > 0 {
+		if resp.ContentLength > f.maxBodySize {
+			return nil, f.bodyTooLarge(url)
+		}
+
+		// Content-Length may be missing or wrong, so one byte more than
+		// allowed is read to detect an oversized body.
+		body = io.LimitReader(resp.Body, f.maxBodySize+1)
+	}
+
+	bs, err := io.ReadAll(body)
+	if err != nil {
+		// The connection was most likely reset in the middle of the body.
+		return nil, &retryableError{err: fmt.Errorf("failed reading data from %v, %w", url, err)}
+	}
+
+	if f.maxBodySize > 0 && int64(len(bs)) > f.maxBodySize {
+		return nil, f.bodyTooLarge(url)
+	}
+
+	return bs, nil
+}
+
+func (f Fetcher) bodyTooLarge(url string) error {
+	return &RejectedError{
+		Url:    url,
+		Err:    ErrBodyTooLarge,
+		Detail: fmt.Sprintf("the limit is %v bytes", f.maxBodySize),
+	}
+}
+
+// limitedBody fails with tooLarge once more than left bytes are read.
+type limitedBody struct {
+	io.ReadCloser
+	left     int64
+	tooLarge error
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if int64(len(p)) > b.left+1 {
+		p = p[:b.left+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) > b.left {
+		n = int(b.left)
+		b.left = 0
+
+		return n, b.tooLarge
+	}
+
+	b.left -= int64(n)
 
-	return io.ReadAll(resp.Body)
+	return n, err
 }
@@ -7,7 +7,10 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -29,23 +32,79 @@ func TestFetch(t *testing.T) {
 		defer server.Close()
 
 		fetcher := New(http.DefaultClient)
-		bs, err := fetcher.Fetch(ctx, server.URL)
+		resp, err := fetcher.Fetch(ctx, server.URL, Validators{})
 
 		require.NoError(t, err)
-		require.Equal(t, content, string(bs))
+		require.Equal(t, content, string(resp.Body))
+		require.False(t, resp.NotModified)
+	})
+
+	t.Run("ConditionalRequest", func(t *testing.T) {
+		const (
+			etag         = `"v1"`
+			lastModified = "Sun, 27 Jul 2025 10:45:00 GMT"
+		)
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Header.Get("If-None-Match") == etag && req.Header.Get("If-Modified-Since") == lastModified {
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", lastModified)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		fetcher := New(http.DefaultClient)
+
+		resp, err := fetcher.Fetch(ctx, server.URL, Validators{})
+
+		require.NoError(t, err)
+		require.False(t, resp.NotModified)
+		require.Equal(t, Validators{Etag: etag, LastModified: lastModified}, resp.Validators)
+
+		resp, err = fetcher.Fetch(ctx, server.URL, resp.Validators)
+
+		require.NoError(t, err)
+		require.True(t, resp.NotModified)
+		require.Empty(t, resp.Body)
+		require.Equal(t, Validators{Etag: etag, LastModified: lastModified}, resp.Validators)
+	})
+
+	t.Run("ContentEncoding", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, "gzip, deflate, br", req.Header.Get("Accept-Encoding"))
+
+			w.Header().Set("Content-Encoding", "br")
+			w.WriteHeader(http.StatusOK)
+			if _, err := fmt.Fprint(w, "compressed"); err != nil {
+				t.Errorf("failed to write response: %v", err)
+			}
+		}))
+		defer server.Close()
+
+		fetcher := New(http.DefaultClient)
+		resp, err := fetcher.Fetch(ctx, server.URL, Validators{})
+
+		require.NoError(t, err)
+		require.Equal(t, "br", resp.ContentEncoding)
+		require.Equal(t, "compressed", string(resp.Body))
 	})
 
 	t.Run("InvalidURL", func(t *testing.T) {
 		fetcher := New(&mock.MockHTTPClient{})
-		bs, err := fetcher.Fetch(ctx, "::://invalid-url")
+		resp, err := fetcher.Fetch(ctx, "::://invalid-url", Validators{})
 
 		require.Error(t, err)
-		require.Nil(t, bs)
+		require.Nil(t, resp.Body)
 	})
 
 	t.Run("NetworkError", func(t *testing.T) {
 		fetcher := New(http.DefaultClient)
-		_, err := fetcher.Fetch(ctx, "https://invalid.url")
+		_, err := fetcher.Fetch(ctx, "https://invalid.url", Validators{})
 
 		require.Error(t, err)
 	})
@@ -66,7 +125,7 @@ func TestFetch(t *testing.T) {
 		}
 
 		fetcher := New(&mock.MockHTTPClient{Resp: resp})
-		_, err := fetcher.Fetch(ctx, "http://example.com")
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
 
 		require.NoError(t, err)
 	})
@@ -78,9 +137,369 @@ func TestFetch(t *testing.T) {
 		defer server.Close()
 
 		fetcher := New(http.DefaultClient)
-		bs, err := fetcher.Fetch(ctx, server.URL)
+		resp, err := fetcher.Fetch(ctx, server.URL, Validators{})
 
 		require.Error(t, err)
-		require.Nil(t, bs)
+		require.Nil(t, resp.Body)
+	})
+}
+
+func TestFetch_Retry(t *testing.T) {
+	ctx := context.Background()
+
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0,
+	}
+
+	t.Run("RetriesServerErrors", func(t *testing.T) {
+		codes := []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK}
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				code := codes[calls]
+				calls++
+
+				return newResponse(code, nil, "Content"), nil
+			},
+		}
+
+		fetcher, delays := newTestFetcher(client, policy)
+
+		resp, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.NoError(t, err)
+		require.Equal(t, "Content", string(resp.Body))
+		require.Equal(t, 3, calls)
+		require.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+	})
+
+	t.Run("GivesUpAfterMaxAttempts", func(t *testing.T) {
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+
+				return newResponse(http.StatusInternalServerError, nil, ""), nil
+			},
+		}
+
+		fetcher, _ := newTestFetcher(client, policy)
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.EqualError(t, err, "unexpected status code: 500")
+		require.Equal(t, policy.MaxAttempts, calls)
+	})
+
+	t.Run("DoesNotRetryClientErrors", func(t *testing.T) {
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+
+				return newResponse(http.StatusNotFound, nil, ""), nil
+			},
+		}
+
+		fetcher, _ := newTestFetcher(client, policy)
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		var statusErr *StatusError
+		require.ErrorAs(t, err, &statusErr)
+		require.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("RespectsRetryAfter", func(t *testing.T) {
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return newResponse(http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}, ""), nil
+				}
+
+				return newResponse(http.StatusOK, nil, ""), nil
+			},
+		}
+
+		fetcher, delays := newTestFetcher(client, policy)
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.NoError(t, err)
+		require.Equal(t, []time.Duration{7 * time.Second}, *delays)
+	})
+
+	t.Run("RetryAfterTooLong", func(t *testing.T) {
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+
+				return newResponse(http.StatusServiceUnavailable, http.Header{"Retry-After": {"3600"}}, ""), nil
+			},
+		}
+
+		fetcher, _ := newTestFetcher(client, policy)
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.Error(t, err)
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("ConnectionReset", func(t *testing.T) {
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				calls++
+				if calls == 1 {
+					return nil, syscall.ECONNRESET
+				}
+
+				return newResponse(http.StatusOK, nil, "Content"), nil
+			},
+		}
+
+		fetcher, _ := newTestFetcher(client, policy)
+
+		resp, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.NoError(t, err)
+		require.Equal(t, "Content", string(resp.Body))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				cancel()
+
+				return newResponse(http.StatusServiceUnavailable, nil, ""), nil
+			},
+		}
+
+		fetcher := New(client, WithRetryPolicy(policy))
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("ContextCancelledWhileWaiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				time.AfterFunc(10*time.Millisecond, cancel)
+
+				return newResponse(http.StatusServiceUnavailable, nil, ""), nil
+			},
+		}
+
+		fetcher := New(client, WithRetryPolicy(policy))
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		header := http.Header{"Content-Encoding": {"gzip"}, "Etag": {`"v1"`}}
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusOK, header, "Content")}
+
+		stream, err := New(client).Open(ctx, "http://example.com", Validators{})
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(stream.Body)
+
+		require.NoError(t, err)
+		require.Equal(t, "Content", string(body))
+		require.Equal(t, "gzip", stream.ContentEncoding)
+		require.Equal(t, Validators{Etag: `"v1"`}, stream.Validators)
+		require.False(t, stream.NotModified)
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		validators := Validators{Etag: `"v1"`}
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusNotModified, nil, "")}
+
+		stream, err := New(client).Open(ctx, "http://example.com", validators)
+
+		require.NoError(t, err)
+		require.True(t, stream.NotModified)
+		require.Equal(t, validators, stream.Validators)
+		require.Nil(t, stream.Body)
+	})
+
+	t.Run("RetriesBeforeTheBody", func(t *testing.T) {
+		codes := []int{http.StatusServiceUnavailable, http.StatusOK}
+		calls := 0
+
+		client := &mock.MockHTTPClient{
+			DoFunc: func(req *http.Request) (*http.Response, error) {
+				code := codes[calls]
+				calls++
+
+				return newResponse(code, nil, "Content"), nil
+			},
+		}
+
+		fetcher, _ := newTestFetcher(client, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Second})
+
+		stream, err := fetcher.Open(ctx, "http://example.com", Validators{})
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(stream.Body)
+
+		require.NoError(t, err)
+		require.Equal(t, "Content", string(body))
+		require.Equal(t, 2, calls)
+	})
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusOK, nil, "0123456789")}
+
+		stream, err := New(client, WithMaxBodySize(5)).Open(ctx, "http://example.com", Validators{})
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(stream.Body)
+
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+		require.Equal(t, "01234", string(body))
+	})
+
+	t.Run("BodyWithinLimit", func(t *testing.T) {
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusOK, nil, "01234")}
+
+		stream, err := New(client, WithMaxBodySize(5)).Open(ctx, "http://example.com", Validators{})
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(stream.Body)
+
+		require.NoError(t, err)
+		require.Equal(t, "01234", string(body))
+	})
+
+	t.Run("ContentLengthTooLarge", func(t *testing.T) {
+		resp := newResponse(http.StatusOK, nil, "")
+		resp.ContentLength = 1 << 40
+
+		_, err := New(&mock.MockHTTPClient{Resp: resp}, WithMaxBodySize(5)).Open(ctx, "http://example.com", Validators{})
+
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+	})
+}
+
+// newTestFetcher records the delays between attempts instead of sleeping.
+func newTestFetcher(client HTTPClient, policy RetryPolicy) (Fetcher, *[]time.Duration) {
+	delays := []time.Duration{}
+
+	fetcher := New(client, WithRetryPolicy(policy))
+	fetcher.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+
+		return nil
+	}
+
+	return fetcher, &delays
+}
+
+func newResponse(code int, header http.Header, body string) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func TestFetch_Limits(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusOK, nil, "0123456789")}
+
+		fetcher := New(client, WithMaxBodySize(5))
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		var rejected *RejectedError
+		require.ErrorAs(t, err, &rejected)
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+		require.Equal(t, "http://example.com", rejected.Url)
+	})
+
+	t.Run("ContentLengthTooLarge", func(t *testing.T) {
+		resp := newResponse(http.StatusOK, nil, "")
+		resp.ContentLength = 1 << 40
+
+		fetcher := New(&mock.MockHTTPClient{Resp: resp}, WithMaxBodySize(5))
+
+		_, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.ErrorIs(t, err, ErrBodyTooLarge)
+	})
+
+	t.Run("BodyWithinLimit", func(t *testing.T) {
+		client := &mock.MockHTTPClient{Resp: newResponse(http.StatusOK, nil, "01234")}
+
+		fetcher := New(client, WithMaxBodySize(5))
+
+		resp, err := fetcher.Fetch(ctx, "http://example.com", Validators{})
+
+		require.NoError(t, err)
+		require.Equal(t, "01234", string(resp.Body))
+	})
+
+	t.Run("UnsupportedContentType", func(t *testing.T) {
+		for _, contentType := range []string{"video/mp4", "image/png", "application/zip"} {
+			client := &mock.MockHTTPClient{
+				Resp: newResponse(http.StatusOK, http.Header{"Content-Type": {contentType}}, ""),
+			}
+
+			_, err := New(client).Fetch(ctx, "http://example.com", Validators{})
+
+			require.ErrorIs(t, err, ErrUnsupportedContentType, contentType)
+		}
+	})
+
+	t.Run("FeedContentTypes", func(t *testing.T) {
+		contentTypes := []string{
+			"application/rss+xml; charset=utf-8",
+			"application/atom+xml",
+			"application/feed+json",
+			"text/xml",
+			"text/html",
+			"application/octet-stream",
+			"",
+		}
+
+		for _, contentType := range contentTypes {
+			client := &mock.MockHTTPClient{
+				Resp: newResponse(http.StatusOK, http.Header{"Content-Type": {contentType}}, ""),
+			}
+
+			_, err := New(client).Fetch(ctx, "http://example.com", Validators{})
+
+			require.NoError(t, err, contentType)
+		}
 	})
 }
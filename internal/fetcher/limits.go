@@ -0,0 +1,71 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+)
+
+// defaultMaxBodySize is used unless WithMaxBodySize says otherwise.
+const defaultMaxBodySize = 10 << 20
+
+var (
+	ErrBodyTooLarge           = errors.New("response body is too large")
+	ErrUnsupportedContentType = errors.New("content type is not a feed")
+)
+
+// RejectedError is returned when a URL is refused because of what it serves
+// rather than a network or server failure, so retrying is pointless. Err is
+// ErrBodyTooLarge or ErrUnsupportedContentType.
+type RejectedError struct {
+	Url    string
+	Err    error
+	Detail string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("%v was rejected: %v (%v)", e.Url, e.Err, e.Detail)
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// WithMaxBodySize limits how many bytes are read from a response; larger
+// responses fail with ErrBodyTooLarge. A size of 0 or less disables the limit.
+func WithMaxBodySize(size int64) Option {
+	return func(f *Fetcher) {
+		f.maxBodySize = size
+	}
+}
+
+// isUnsupportedContentType reports the content types that can't be a feed.
+// Unknown and generic types pass, since feeds are often served with
+// text/plain, text/html or application/octet-stream.
+func isUnsupportedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, prefix := range []string{"image/", "video/", "audio/", "font/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+
+	switch mediaType {
+	case "application/zip",
+		"application/x-zip-compressed",
+		"application/x-rar-compressed",
+		"application/x-7z-compressed",
+		"application/x-tar",
+		"application/pdf",
+		"application/msword",
+		"application/x-msdownload":
+		return true
+	}
+
+	return false
+}
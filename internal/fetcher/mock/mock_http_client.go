@@ -2,12 +2,19 @@ package mock
 
 import "net/http"
 
+// MockHTTPClient returns Resp and Err, unless DoFunc is set, which allows
+// answering each request differently.
 type MockHTTPClient struct {
-	Resp *http.Response
-	Err  error
+	Resp   *http.Response
+	Err    error
+	DoFunc func(req *http.Request) (*http.Response, error)
 }
 
 func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	if m.DoFunc != nil {
+		return m.DoFunc(req)
+	}
+
 	if m.Err != nil {
 		return nil, m.Err
 	}
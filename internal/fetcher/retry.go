@@ -0,0 +1,104 @@
+package fetcher
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how failed requests are retried. Connection errors,
+// 429 and 5xx responses are retried; the delay doubles with every attempt,
+// starting at BaseDelay and capped at MaxDelay, unless the server asks for a
+// specific delay with Retry-After.
+type RetryPolicy struct {
+	// MaxAttempts includes the first request; 1 or less disables retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Jitter is the fraction of each delay that is randomized, from 0 to 1,
+	// so that many feeds failing at once aren't retried in lockstep.
+	Jitter float64
+}
+
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(f *Fetcher) {
+		f.retryPolicy = p
+	}
+}
+
+// retryableError marks a failure worth another attempt.
+type retryableError struct {
+	err error
+	// retryAfter is the delay requested by the server, if any.
+	retryAfter time.Duration
+}
+
+func (e *retryableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryableError) Unwrap() error {
+	return e.err
+}
+
+// delay returns how long to wait before attempt+1 and whether to retry at all.
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if retryAfter > 0 {
+		// Retrying earlier than the server asked is pointless, and waiting
+		// longer than we are willing to would stall the import.
+		return retryAfter, retryAfter <= p.MaxDelay
+	}
+
+	d := p.BaseDelay << (attempt - 1)
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * min(p.Jitter, 1) * rand.Float64()) //nolint:gosec
+	}
+
+	return d, true
+}
+
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter accepts both forms of the header: delay in seconds and an
+// HTTP date.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0)
+	}
+
+	return 0
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
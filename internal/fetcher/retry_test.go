@@ -0,0 +1,82 @@
+package fetcher
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+		MaxDelay:    5 * time.Second,
+		Jitter:      0,
+	}
+
+	t.Run("ExponentialBackoff", func(t *testing.T) {
+		for attempt, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+			delay, ok := policy.delay(attempt+1, 0)
+
+			require.True(t, ok)
+			require.Equal(t, expected, delay)
+		}
+
+		_, ok := policy.delay(policy.MaxAttempts, 0)
+		require.False(t, ok)
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		jittered := policy
+		jittered.Jitter = 0.5
+
+		for range 100 {
+			delay, ok := jittered.delay(2, 0)
+
+			require.True(t, ok)
+			require.GreaterOrEqual(t, delay, time.Second)
+			require.LessOrEqual(t, delay, 2*time.Second)
+		}
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		delay, ok := policy.delay(1, 3*time.Second)
+
+		require.True(t, ok)
+		require.Equal(t, 3*time.Second, delay)
+
+		_, ok = policy.delay(1, time.Minute)
+		require.False(t, ok)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		_, ok := RetryPolicy{}.delay(1, 0)
+
+		require.False(t, ok)
+	})
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 7, 27, 10, 45, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   string
+		expected time.Duration
+	}{
+		{name: "Seconds", header: "120", expected: 2 * time.Minute},
+		{name: "HttpDate", header: now.Add(30 * time.Second).Format(http.TimeFormat), expected: 30 * time.Second},
+		{name: "DateInPast", header: now.Add(-time.Minute).Format(http.TimeFormat), expected: 0},
+		{name: "Negative", header: "-5", expected: 0},
+		{name: "Empty", header: "", expected: 0},
+		{name: "Invalid", header: "soon", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, parseRetryAfter(tt.header, now))
+		})
+	}
+}
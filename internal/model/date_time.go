@@ -1,30 +1,64 @@
 package model
 
 import (
-	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
 )
 
 type DateTime time.Time
 
-const rssItemDateTimeLayout = "Mon, _2 Jan 2006 15:04:05 -0700"
+var ErrInvalidDateTime = errors.New("unrecognized date format")
 
-func (dt *DateTime) UnmarshalXML(d *xml.Decoder, se xml.StartElement) error {
-	var dtStr string
-
-	err := d.DecodeElement(&dtStr, &se)
-	if err != nil {
-		return err
+// dateTimeLayouts are tried in order, most common first. Weekdays, named time
+// zones and colons in numeric offsets are normalized away beforehand, so every
+// layout only has to deal with the remaining variations. Layouts without a
+// zone are interpreted as UTC.
+func dateTimeLayouts() []string {
+	return []string{
+		// RFC 822 / RFC 1123 and their common deviations
+		"_2 Jan 2006 15:04:05 -0700",
+		"_2 Jan 2006 15:04 -0700",
+		"_2 Jan 06 15:04:05 -0700",
+		"_2 Jan 06 15:04 -0700",
+		"_2 January 2006 15:04:05 -0700",
+		"_2 January 2006 15:04 -0700",
+		"_2 Jan 2006 15:04:05",
+		"_2 Jan 2006 15:04",
+		"_2 Jan 2006",
+		"_2-Jan-06 15:04:05 -0700",
+		"_2-Jan-2006 15:04:05 -0700",
+		"Jan _2 15:04:05 2006",
+		"Jan _2 15:04:05 -0700 2006",
+		"January _2, 2006 15:04:05 -0700",
+		"January _2, 2006",
+		// RFC 3339 / ISO 8601
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05Z0700",
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05 -0700",
+		"2006-01-02 15:04:05",
+		"2006-01-02 15:04",
+		time.DateOnly,
 	}
+}
 
-	t, err := time.Parse(rssItemDateTimeLayout, dtStr)
-	if err != nil {
-		return err
-	}
+// ParseDateTime parses dates found in feeds, which often deviate from the
+// RFC 822 format required by RSS: missing or misspelled weekdays, two-digit
+// years, named time zones and ISO 8601 dates are all accepted.
+func ParseDateTime(s string) (DateTime, error) {
+	normalized := normalizeDateTime(s)
 
-	*dt = DateTime(t)
+	for _, layout := range dateTimeLayouts() {
+		if t, err := time.Parse(layout, normalized); err == nil {
+			return DateTime(t), nil
+		}
+	}
 
-	return nil
+	return DateTime{}, fmt.Errorf("%w: %q", ErrInvalidDateTime, s)
 }
 
 func (dt *DateTime) Format(layout string) string {
@@ -34,3 +68,124 @@ func (dt *DateTime) Format(layout string) string {
 func (dt *DateTime) String() string {
 	return dt.Format(time.DateTime)
 }
+
+func (dt *DateTime) IsZero() bool {
+	return time.Time(*dt).IsZero()
+}
+
+// normalizeDateTime rewrites the parts of a date that can't be expressed with
+// time.Parse layouts: weekday names are dropped, a trailing comment like
+// "(PDT)" is removed and named zones are replaced with numeric offsets.
+func normalizeDateTime(s string) string {
+	fields := strings.Fields(s)
+
+	if len(fields) > 0 && isWeekday(strings.TrimSuffix(fields[0], ",")) {
+		fields = fields[1:]
+	}
+
+	if n := len(fields); n > 1 && strings.HasPrefix(fields[n-1], "(") && strings.HasSuffix(fields[n-1], ")") {
+		fields = fields[:n-1]
+	}
+
+	for i, f := range fields {
+		if offset, ok := zoneOffset(f); ok {
+			fields[i] = formatOffset(offset)
+		} else if isNumericOffset(f) {
+			fields[i] = strings.Replace(f, ":", "", 1)
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+func isWeekday(s string) bool {
+	const minWeekdayLength = 3
+
+	if len(s) < minWeekdayLength {
+		return false
+	}
+
+	s = strings.ToLower(s)
+
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.HasPrefix(strings.ToLower(d.String()), s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNumericOffset reports whether s looks like "+03:00" or "-0530".
+func isNumericOffset(s string) bool {
+	if len(s) < 2 || (s[0] != '+' && s[0] != '-') {
+		return false
+	}
+
+	for _, r := range s[1:] {
+		if !unicode.IsDigit(r) && r != ':' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// zoneOffset resolves the zone names allowed by RFC 822 plus the ones that
+// commonly show up in feeds anyway. The offset is returned in minutes.
+func zoneOffset(name string) (int, bool) {
+	const hour = 60
+
+	switch strings.ToUpper(name) {
+	case "UT", "UTC", "GMT", "Z", "WET":
+		return 0, true
+	case "BST", "CET", "WEST":
+		return 1 * hour, true
+	case "CEST", "EET":
+		return 2 * hour, true
+	case "EEST", "MSK":
+		return 3 * hour, true
+	case "IST":
+		return 5*hour + 30, true
+	case "HKT", "SGT", "AWST":
+		return 8 * hour, true
+	case "JST", "KST":
+		return 9 * hour, true
+	case "AEST":
+		return 10 * hour, true
+	case "AEDT":
+		return 11 * hour, true
+	case "NZST":
+		return 12 * hour, true
+	case "NZDT":
+		return 13 * hour, true
+	case "EDT":
+		return -4 * hour, true
+	case "EST", "CDT":
+		return -5 * hour, true
+	case "CST", "MDT":
+		return -6 * hour, true
+	case "MST", "PDT":
+		return -7 * hour, true
+	case "PST", "AKDT":
+		return -8 * hour, true
+	case "AKST":
+		return -9 * hour, true
+	case "HST":
+		return -10 * hour, true
+	}
+
+	return 0, false
+}
+
+func formatOffset(minutes int) string {
+	const minutesPerHour = 60
+
+	sign := '+'
+	if minutes < 0 {
+		sign = '-'
+		minutes = -minutes
+	}
+
+	return fmt.Sprintf("%c%02d%02d", sign, minutes/minutesPerHour, minutes%minutesPerHour)
+}
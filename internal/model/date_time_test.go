@@ -1,7 +1,6 @@
 package model
 
 import (
-	"encoding/xml"
 	"testing"
 	"time"
 
@@ -10,43 +9,58 @@ import (
 
 var expectedDateTime = time.Date(2025, 7, 27, 13, 45, 0, 0, time.FixedZone("UTC+3", 3*60*60))
 
-func TestUnmarshalXML(t *testing.T) {
-	t.Run("Success", func(t *testing.T) {
-		const itemXml = `
-			<item>
-				<pubDate>Sat, 27 Jul 2025 13:45:00 +0300</pubDate>
-			</item>`
+func TestParseDateTime(t *testing.T) {
+	utc := time.UTC
+	est := time.FixedZone("EST", -5*60*60)
+	ist := time.FixedZone("IST", 5*60*60+30*60)
 
-		var item Item
-		err := xml.Unmarshal([]byte(itemXml), &item)
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+	}{
+		{"RFC1123Z", "Sat, 27 Jul 2025 13:45:00 +0300", expectedDateTime},
+		{"SingleDigitDay", "Sun, 3 Aug 2025 08:00:00 +0000", time.Date(2025, 8, 3, 8, 0, 0, 0, utc)},
+		{"GMT", "Sat, 27 Jul 2025 10:45:00 GMT", expectedDateTime},
+		{"NamedZone", "Sat, 27 Jul 2025 05:45:00 EST", expectedDateTime},
+		{"HalfHourZone", "Sat, 27 Jul 2025 16:15:00 IST", time.Date(2025, 7, 27, 16, 15, 0, 0, ist)},
+		{"MissingWeekday", "27 Jul 2025 13:45:00 +0300", expectedDateTime},
+		{"LongWeekday", "Saturday, 27 Jul 2025 13:45:00 +0300", expectedDateTime},
+		{"MisspelledWeekday", "Satur, 27 Jul 2025 13:45:00 +0300", expectedDateTime},
+		{"WrongWeekday", "Mon, 27 Jul 2025 13:45:00 +0300", expectedDateTime},
+		{"TwoDigitYear", "Sat, 27 Jul 25 13:45:00 +0300", expectedDateTime},
+		{"NoSeconds", "Sat, 27 Jul 2025 13:45 +0300", expectedDateTime},
+		{"FullMonth", "Sat, 27 July 2025 13:45:00 +0300", expectedDateTime},
+		{"UpperCaseMonth", "SAT, 27 JUL 2025 13:45:00 +0300", expectedDateTime},
+		{"ColonInOffset", "Sat, 27 Jul 2025 13:45:00 +03:00", expectedDateTime},
+		{"TrailingComment", "Sat, 27 Jul 2025 00:45:00 -0500 (EST)", time.Date(2025, 7, 27, 0, 45, 0, 0, est)},
+		{"NoZone", "Sat, 27 Jul 2025 10:45:00", expectedDateTime},
+		{"ExtraWhitespace", "  Sat,  27 Jul   2025 13:45:00 +0300 ", expectedDateTime},
+		{"RFC850", "Saturday, 27-Jul-25 10:45:00 GMT", expectedDateTime},
+		{"ANSIC", "Sat Jul 27 10:45:00 2025", expectedDateTime},
+		{"RFC3339", "2025-07-27T13:45:00+03:00", expectedDateTime},
+		{"RFC3339Nano", "2025-07-27T10:45:00.000Z", expectedDateTime},
+		{"ISONoColon", "2025-07-27T13:45:00+0300", expectedDateTime},
+		{"ISONoSeconds", "2025-07-27T10:45Z", expectedDateTime},
+		{"ISONoZone", "2025-07-27T10:45:00", expectedDateTime},
+		{"ISOWithSpace", "2025-07-27 13:45:00 +03:00", expectedDateTime},
+		{"DateOnly", "2025-07-27", time.Date(2025, 7, 27, 0, 0, 0, 0, utc)},
+	}
 
-		require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseDateTime(tt.input)
 
-		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
-	})
-
-	t.Run("InvalidFormat", func(t *testing.T) {
-		const badItemXml = `
-			<item>
-				<pubDate>Invalid date</pubDate>
-			</item>`
-
-		var item Item
-		err := xml.Unmarshal([]byte(badItemXml), &item)
-
-		require.Error(t, err)
-	})
-
-	t.Run("MalformedXml", func(t *testing.T) {
-		const malformedXml = `
-			<item>
-				<pubDate>Invalid date
-			</item>`
+			require.NoError(t, err)
+			require.True(t, time.Time(actual).Equal(tt.expected), "got %v", time.Time(actual))
+		})
+	}
 
-		var item Item
-		err := xml.Unmarshal([]byte(malformedXml), &item)
+	t.Run("Invalid", func(t *testing.T) {
+		actual, err := ParseDateTime("sometime last week")
 
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrInvalidDateTime)
+		require.True(t, actual.IsZero())
 	})
 }
 
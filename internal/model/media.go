@@ -0,0 +1,54 @@
+package model
+
+// Attachment is a media object of an item described with Media RSS
+// (http://search.yahoo.com/mrss/), such as a video, an image or a song.
+type Attachment struct {
+	Url    string
+	Type   string
+	Medium string
+	Width  int
+	Height int
+	// FileSize is in bytes and Duration in seconds, both are 0 when unknown.
+	FileSize     int64
+	Duration     int
+	Description  string
+	Credit       string
+	ThumbnailUrl string
+}
+
+// MediaRss holds the Media RSS elements of an item as found in the feed. The
+// parser turns them into attachments. An item-level <media:description> is
+// not read, since it can't be told apart from the item's own <description>.
+type MediaRss struct {
+	MediaContents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroups     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+	MediaThumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaCredits    []string         `xml:"http://search.yahoo.com/mrss/ credit"`
+}
+
+// MediaGroup lists alternative versions of the same media object.
+type MediaGroup struct {
+	Contents    []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails  []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Description string           `xml:"http://search.yahoo.com/mrss/ description"`
+	Credits     []string         `xml:"http://search.yahoo.com/mrss/ credit"`
+}
+
+// MediaContent keeps its numeric attributes as strings, so a malformed one
+// doesn't fail the whole feed.
+type MediaContent struct {
+	Url         string           `xml:"url,attr"`
+	Type        string           `xml:"type,attr"`
+	Medium      string           `xml:"medium,attr"`
+	Width       string           `xml:"width,attr"`
+	Height      string           `xml:"height,attr"`
+	FileSize    string           `xml:"fileSize,attr"`
+	Duration    string           `xml:"duration,attr"`
+	Thumbnails  []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Description string           `xml:"http://search.yahoo.com/mrss/ description"`
+	Credits     []string         `xml:"http://search.yahoo.com/mrss/ credit"`
+}
+
+type MediaThumbnail struct {
+	Url string `xml:"url,attr"`
+}
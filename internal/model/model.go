@@ -1,7 +1,14 @@
 package model
 
+import (
+	"encoding/xml"
+	"strings"
+)
+
 type Rss struct {
 	Channels []Channel `xml:"channel"`
+	// Warnings describe what was repaired to decode a malformed document.
+	Warnings []string `xml:"-"`
 }
 
 type Channel struct {
@@ -9,12 +16,136 @@ type Channel struct {
 	Title       string `xml:"title"`
 	Language    string `xml:"language"`
 	Description string `xml:"description"`
-	Items       []Item `xml:"item"`
+	// SourceUrl is the URL the feed was imported from.
+	SourceUrl string `xml:"-"`
+	Link      string `xml:"-"`
+	// Links collects every <link> of the channel, including atom:link
+	// elements, which share the local name. Link is the first non-empty one.
+	Links     []string `json:"-" xml:"link"`
+	ImageUrl  string   `xml:"image>url"`
+	Generator string   `xml:"generator"`
+	// LastBuildDate is parsed from RawLastBuildDate by the parser, which
+	// drops a date it can't read with a warning.
+	LastBuildDate    DateTime `xml:"-"`
+	RawLastBuildDate string   `json:"-" xml:"lastBuildDate"`
+	// Ttl (minutes), SkipHours (0-23, GMT), SkipDays, UpdatePeriod and
+	// UpdateFrequency are the publisher's polling hints. The raw values are
+	// normalized by the parser, so a malformed hint doesn't fail the whole
+	// feed.
+	Ttl                int      `xml:"-"`
+	SkipHours          []int    `xml:"-"`
+	SkipDays           []string `xml:"skipDays>day"`
+	UpdatePeriod       string   `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency    int      `xml:"-"`
+	RawTtl             string   `json:"-" xml:"ttl"`
+	RawSkipHours       []string `json:"-" xml:"skipHours>hour"`
+	RawUpdateFrequency string   `json:"-" xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+	// Etag and LastModified are the HTTP cache validators of the last fetch.
+	Etag         string `xml:"-"`
+	LastModified string `xml:"-"`
+	// NextFetchAt is when the scheduler refreshes the channel next.
+	NextFetchAt DateTime `xml:"-"`
+	// LastFetchedAt, LastErrorAt and LastError track background refreshes.
+	LastFetchedAt DateTime `xml:"-"`
+	LastErrorAt   DateTime `xml:"-"`
+	LastError     string   `xml:"-"`
+	// Folder groups channels imported from a subscription list, nested
+	// folders are separated by "/".
+	Folder string `xml:"-"`
+	// XmlBase is the xml:base the relative URLs of the channel are resolved
+	// against on import, if the feed declares one.
+	XmlBase string `json:"-" xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	// Categories also picks up itunes:category, whose name is an attribute.
+	// The parser drops such nameless categories.
+	Categories []Category `xml:"category"`
+	Items      []Item     `xml:"item"`
 }
 
 type Item struct {
-	Id          int      `xml:"-"`
-	Title       string   `xml:"title"`
-	Description string   `xml:"description"`
-	PubDate     DateTime `xml:"pubDate"`
+	Id          int    `xml:"-"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	// Content is the full article from <content:encoded> or an Atom
+	// <content>, while Description is its summary.
+	Content string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	// PubDate is parsed from RawPubDate like the LastBuildDate of a channel.
+	PubDate    DateTime `xml:"-"`
+	RawPubDate string   `json:"-" xml:"pubDate"`
+	Link       string   `xml:"link"`
+	Guid       Guid     `xml:"guid"`
+	Author     string   `xml:"author"`
+	// Creator is the Dublin Core alternative to Author used by many feeds.
+	Creator    string      `json:"-" xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Enclosures []Enclosure `xml:"enclosure"`
+	// Duration (in seconds), Episode, Season, ImageUrl and Explicit come from
+	// the iTunes podcast namespace. The raw values are normalized by the
+	// parser, so a malformed one doesn't fail the whole feed.
+	Duration    int         `xml:"-"`
+	Episode     int         `xml:"-"`
+	Season      int         `xml:"-"`
+	ImageUrl    string      `xml:"-"`
+	Explicit    bool        `xml:"-"`
+	RawDuration string      `json:"-" xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	RawEpisode  string      `json:"-" xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	RawSeason   string      `json:"-" xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	RawExplicit string      `json:"-" xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+	RawImage    ItunesImage `json:"-" xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	// Attachments and ThumbnailUrl come from the Media RSS elements.
+	Attachments  []Attachment `xml:"-"`
+	ThumbnailUrl string       `xml:"-"`
+	MediaRss     `json:"-"`
+	Categories   []Category `xml:"category"`
+	// XmlBase overrides the xml:base of the channel for the item.
+	XmlBase string `json:"-" xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+}
+
+// Enclosure is a media file attached to an item, usually a podcast episode.
+type Enclosure struct {
+	Url  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+	// Length is the size in bytes, or 0 when unknown.
+	Length    int64  `xml:"-"`
+	RawLength string `json:"-" xml:"length,attr"`
+}
+
+type ItunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+type Guid struct {
+	Value       string
+	IsPermaLink bool
+}
+
+// UnmarshalXML applies the RSS default of isPermaLink="true" when the
+// attribute is missing.
+func (g *Guid) UnmarshalXML(d *xml.Decoder, se xml.StartElement) error {
+	g.IsPermaLink = true
+
+	for _, attr := range se.Attr {
+		if attr.Name.Local == "isPermaLink" {
+			g.IsPermaLink = strings.TrimSpace(attr.Value) != "false"
+		}
+	}
+
+	if err := d.DecodeElement(&g.Value, &se); err != nil {
+		return err
+	}
+
+	g.Value = strings.TrimSpace(g.Value)
+
+	return nil
+}
+
+// Category files a channel or an item under a name. Domain identifies the
+// taxonomy the name belongs to, if the feed gives one.
+type Category struct {
+	Name   string `xml:",chardata"`
+	Domain string `xml:"domain,attr"`
+}
+
+// CategoryCount is a category name with the number of items filed under it.
+type CategoryCount struct {
+	Name  string
+	Count int
 }
@@ -0,0 +1,96 @@
+package opml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// ExportTitle is the title of exported subscription lists.
+const ExportTitle = "RSS Feed Parser subscriptions"
+
+// FromChannels lists the subscriptions of the stored channels. Channels
+// imported from the same document share their source URL, so only the first
+// of them is listed.
+func FromChannels(channels []model.Channel) []Feed {
+	feeds := make([]Feed, 0, len(channels))
+	seen := make(map[string]struct{}, len(channels))
+
+	for _, ch := range channels {
+		if ch.SourceUrl == "" {
+			continue
+		}
+
+		if _, ok := seen[ch.SourceUrl]; ok {
+			continue
+		}
+
+		seen[ch.SourceUrl] = struct{}{}
+		feeds = append(feeds, Feed{Title: ch.Title, XmlUrl: ch.SourceUrl, HtmlUrl: ch.Link, Folder: ch.Folder})
+	}
+
+	return feeds
+}
+
+// Write renders feeds as an OPML 2.0 document. Folders become nested
+// outlines in the order they first appear.
+func Write(w io.Writer, title string, feeds []Feed, created time.Time) error {
+	doc := Opml{
+		Version: "2.0",
+		Head:    Head{Title: title, DateCreated: created.UTC().Format(time.RFC1123Z)},
+	}
+
+	for _, feed := range feeds {
+		outlines := &doc.Body.Outlines
+
+		if feed.Folder != "" {
+			for _, name := range strings.Split(feed.Folder, folderSeparator) {
+				outlines = &folderOutline(outlines, name).Outlines
+			}
+		}
+
+		text := feed.Title
+		if text == "" {
+			text = feed.XmlUrl
+		}
+
+		*outlines = append(*outlines, Outline{
+			Text:    text,
+			Title:   text,
+			Type:    "rss",
+			XmlUrl:  feed.XmlUrl,
+			HtmlUrl: feed.HtmlUrl,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed writing opml data: %w", err)
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed marshalling opml data: %w", err)
+	}
+
+	return enc.Close()
+}
+
+// folderOutline returns the folder called name among outlines, adding it if
+// there is none yet.
+func folderOutline(outlines *[]Outline, name string) *Outline {
+	for i := range *outlines {
+		if o := &(*outlines)[i]; o.XmlUrl == "" && o.Text == name {
+			return o
+		}
+	}
+
+	*outlines = append(*outlines, Outline{Text: name, Title: name})
+
+	return &(*outlines)[len(*outlines)-1]
+}
@@ -0,0 +1,68 @@
+package opml
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+func TestFromChannels(t *testing.T) {
+	channels := []model.Channel{
+		{Id: 1, Title: "Go blog", SourceUrl: "https://go.dev/blog/feed.atom", Link: "https://go.dev/blog"},
+		{Id: 2, Title: "Second channel", SourceUrl: "https://go.dev/blog/feed.atom"},
+		{Id: 3, Title: "Slashdot", SourceUrl: "https://rss.slashdot.org/", Folder: "News/Tech"},
+		{Id: 4, Title: "Unknown source"},
+	}
+
+	require.Equal(t, []Feed{
+		{Title: "Go blog", XmlUrl: "https://go.dev/blog/feed.atom", HtmlUrl: "https://go.dev/blog"},
+		{Title: "Slashdot", XmlUrl: "https://rss.slashdot.org/", Folder: "News/Tech"},
+	}, FromChannels(channels))
+}
+
+func TestWrite(t *testing.T) {
+	feeds := []Feed{
+		{Title: "Slashdot", XmlUrl: "https://rss.slashdot.org/Slashdot/slashdotMain", Folder: "News/Tech"},
+		{Title: "Go blog & news", XmlUrl: "https://go.dev/blog/feed.atom", HtmlUrl: "https://go.dev/blog"},
+		{XmlUrl: "https://news.ycombinator.com/rss", Folder: "News"},
+	}
+
+	var buf bytes.Buffer
+
+	err := Write(&buf, "Subscriptions", feeds, time.Date(2025, 7, 27, 13, 45, 0, 0, time.UTC))
+
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Subscriptions</title>
+    <dateCreated>Sun, 27 Jul 2025 13:45:00 +0000</dateCreated>
+  </head>
+  <body>
+    <outline text="News" title="News">
+      <outline text="Tech" title="Tech">
+        <outline text="Slashdot" title="Slashdot" type="rss" xmlUrl="https://rss.slashdot.org/Slashdot/slashdotMain"></outline>
+      </outline>
+      <outline text="https://news.ycombinator.com/rss" title="https://news.ycombinator.com/rss" type="rss" xmlUrl="https://news.ycombinator.com/rss"></outline>
+    </outline>
+    <outline text="Go blog &amp; news" title="Go blog &amp; news" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"></outline>
+  </body>
+</opml>`, buf.String())
+
+	parsed, err := Parse(buf.Bytes())
+
+	require.NoError(t, err)
+	require.ElementsMatch(t, []Feed{
+		{Title: "Slashdot", XmlUrl: "https://rss.slashdot.org/Slashdot/slashdotMain", Folder: "News/Tech"},
+		{Title: "Go blog & news", XmlUrl: "https://go.dev/blog/feed.atom", HtmlUrl: "https://go.dev/blog"},
+		{
+			Title:  "https://news.ycombinator.com/rss",
+			XmlUrl: "https://news.ycombinator.com/rss",
+			Folder: "News",
+		},
+	}, parsed)
+}
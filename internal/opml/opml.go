@@ -0,0 +1,103 @@
+package opml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
+)
+
+// folderSeparator joins the names of nested folders.
+const folderSeparator = "/"
+
+// Opml is an OPML 1.0 or 2.0 document (http://opml.org/spec2.opml).
+type Opml struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    Head     `xml:"head"`
+	Body    Body     `xml:"body"`
+}
+
+type Head struct {
+	Title       string `xml:"title"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type Body struct {
+	Outlines []Outline `xml:"outline"`
+}
+
+// Outline is either a feed, when XmlUrl is set, or a folder of outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XmlUrl   string    `xml:"xmlUrl,attr,omitempty"`
+	HtmlUrl  string    `xml:"htmlUrl,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// Feed is a subscription found in an OPML document.
+type Feed struct {
+	Title   string
+	XmlUrl  string
+	HtmlUrl string
+	// Folder is the path of the folders the feed is nested in, e.g.
+	// "News/Tech", or empty for top-level feeds.
+	Folder string
+}
+
+// Parse returns the feeds of an OPML document in document order.
+func Parse(bs []byte) ([]Feed, error) {
+	var doc Opml
+
+	d := xml.NewDecoder(bytes.NewReader(bs))
+	d.CharsetReader = decoder.CharsetReader
+
+	if err := d.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed unmarshalling opml data: %w", err)
+	}
+
+	var feeds []Feed
+
+	collectFeeds(doc.Body.Outlines, "", &feeds)
+
+	return feeds, nil
+}
+
+func collectFeeds(outlines []Outline, folder string, feeds *[]Feed) {
+	for _, o := range outlines {
+		name := strings.TrimSpace(o.Text)
+		if name == "" {
+			name = strings.TrimSpace(o.Title)
+		}
+
+		if xmlUrl := strings.TrimSpace(o.XmlUrl); xmlUrl != "" {
+			*feeds = append(*feeds, Feed{
+				Title:   name,
+				XmlUrl:  xmlUrl,
+				HtmlUrl: strings.TrimSpace(o.HtmlUrl),
+				Folder:  folder,
+			})
+
+			continue
+		}
+
+		subfolder := folder
+		if name != "" {
+			subfolder = joinFolder(folder, name)
+		}
+
+		collectFeeds(o.Outlines, subfolder, feeds)
+	}
+}
+
+func joinFolder(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + folderSeparator + name
+}
@@ -0,0 +1,60 @@
+package opml
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const nestedOpml = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Subscriptions</title>
+  </head>
+  <body>
+    <outline text="Go blog" type="rss" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog"/>
+    <outline text="News">
+      <outline text="Hacker News" type="rss" xmlUrl=" https://news.ycombinator.com/rss "/>
+      <outline title="Tech">
+        <outline title="Slashdot" xmlUrl="https://rss.slashdot.org/Slashdot/slashdotMain"/>
+      </outline>
+      <outline text="Empty folder"/>
+    </outline>
+    <outline text="Not a feed" type="link" url="https://example.com/"/>
+  </body>
+</opml>`
+
+func TestParse(t *testing.T) {
+	t.Run("NestedFolders", func(t *testing.T) {
+		feeds, err := Parse([]byte(nestedOpml))
+
+		require.NoError(t, err)
+		require.Equal(t, []Feed{
+			{Title: "Go blog", XmlUrl: "https://go.dev/blog/feed.atom", HtmlUrl: "https://go.dev/blog"},
+			{Title: "Hacker News", XmlUrl: "https://news.ycombinator.com/rss", Folder: "News"},
+			{Title: "Slashdot", XmlUrl: "https://rss.slashdot.org/Slashdot/slashdotMain", Folder: "News/Tech"},
+		}, feeds)
+	})
+
+	t.Run("Opml1", func(t *testing.T) {
+		const opml1 = `<?xml version="1.0" encoding="ISO-8859-1"?>
+			<opml version="1.0">
+				<head><title>mySubscriptions</title></head>
+				<body>
+					<outline title="Caf` + "\xe9" + `" xmlUrl="http://example.com/cafe.xml"/>
+				</body>
+			</opml>`
+
+		feeds, err := Parse([]byte(opml1))
+
+		require.NoError(t, err)
+		require.Equal(t, []Feed{{Title: "Café", XmlUrl: "http://example.com/cafe.xml"}}, feeds)
+	})
+
+	t.Run("NotOpml", func(t *testing.T) {
+		feeds, err := Parse([]byte(`<rss version="2.0"><channel></channel></rss>`))
+
+		require.Error(t, err)
+		require.Nil(t, feeds)
+	})
+}
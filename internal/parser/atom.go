@@ -0,0 +1,253 @@
+package parser
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+type atomFeed struct {
+	Language   string         `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	XmlBase    string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title      atomText       `xml:"title"`
+	Subtitle   atomText       `xml:"subtitle"`
+	Links      []atomLink     `xml:"link"`
+	Logo       string         `xml:"logo"`
+	Icon       string         `xml:"icon"`
+	Updated    string         `xml:"updated"`
+	Generator  string         `xml:"generator"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Entries    []atomEntry    `xml:"entry"`
+
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+type atomEntry struct {
+	XmlBase    string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Id         string         `xml:"id"`
+	Links      []atomLink     `xml:"link"`
+	Title      atomText       `xml:"title"`
+	Summary    atomText       `xml:"summary"`
+	Content    atomText       `xml:"http://www.w3.org/2005/Atom content"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+
+	model.MediaRss
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+// atomCategory names the category in term, which is meant for machines, and
+// optionally in label, which is meant for people.
+type atomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr"`
+	Label  string `xml:"label,attr"`
+}
+
+type atomLink struct {
+	Rel    string `xml:"rel,attr"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// atomText is an Atom text construct. Its body is kept as HTML regardless of
+// the declared type, so it can be rendered the same way as RSS descriptions.
+type atomText struct {
+	Type string
+	Body string
+}
+
+func (t *atomText) UnmarshalXML(d *xml.Decoder, se xml.StartElement) error {
+	for _, attr := range se.Attr {
+		if attr.Name.Local == "type" {
+			t.Type = attr.Value
+		}
+	}
+
+	if t.Type == "xhtml" {
+		var inner struct {
+			Div struct {
+				Body string `xml:",innerxml"`
+			} `xml:"div"`
+		}
+
+		if err := d.DecodeElement(&inner, &se); err != nil {
+			return err
+		}
+
+		t.Body = strings.TrimSpace(inner.Div.Body)
+
+		return nil
+	}
+
+	if err := d.DecodeElement(&t.Body, &se); err != nil {
+		return err
+	}
+
+	t.Body = strings.TrimSpace(t.Body)
+
+	return nil
+}
+
+// HTML returns the text construct as HTML markup.
+func (t *atomText) HTML() string {
+	if t.Type == "html" || t.Type == "xhtml" {
+		return t.Body
+	}
+
+	return html.EscapeString(t.Body)
+}
+
+// String returns the text construct as plain text.
+func (t *atomText) String() string {
+	if t.Type == "html" {
+		return html.UnescapeString(t.Body)
+	}
+
+	return t.Body
+}
+
+func parseAtom(bs []byte, lenient bool) (model.Rss, error) {
+	var feed atomFeed
+
+	if err := unmarshalXml(bs, &feed, lenient); err != nil {
+		return model.Rss{}, fmt.Errorf("failed unmarshalling atom data: %w", err)
+	}
+
+	var w warnings
+
+	channel := feed.toChannel(&w)
+	channel.Items = make([]model.Item, 0, len(feed.Entries))
+
+	for i := range feed.Entries {
+		channel.Items = append(channel.Items, feed.entryItem(&feed.Entries[i], &w))
+	}
+
+	return model.Rss{Channels: []model.Channel{channel}, Warnings: w}, nil
+}
+
+// toChannel returns the feed metadata as a channel without items.
+func (f *atomFeed) toChannel(w *warnings) model.Channel {
+	image := f.Logo
+	if image == "" {
+		image = f.Icon
+	}
+
+	return model.Channel{
+		Title:           f.Title.String(),
+		Language:        f.Language,
+		Description:     f.Subtitle.String(),
+		Link:            alternateAtomLink(f.Links),
+		ImageUrl:        strings.TrimSpace(image),
+		LastBuildDate:   parseDate(f.Updated, "updated", w),
+		Generator:       strings.TrimSpace(f.Generator),
+		UpdatePeriod:    parseUpdatePeriod(f.UpdatePeriod),
+		UpdateFrequency: parseUpdateFrequency(f.UpdateFrequency),
+		Categories:      atomCategories(f.Categories),
+		XmlBase:         strings.TrimSpace(f.XmlBase),
+	}
+}
+
+// entryItem converts an entry of the feed. Entries inherit the feed author
+// when they don't declare their own.
+func (f *atomFeed) entryItem(e *atomEntry, w *warnings) model.Item {
+	item := e.toItem(w)
+
+	if item.Author == "" {
+		item.Author = joinAtomPersons(f.Authors)
+	}
+
+	return item
+}
+
+func (e *atomEntry) toItem(w *warnings) model.Item {
+	date, dateName := e.Published, "published"
+	if strings.TrimSpace(date) == "" {
+		date, dateName = e.Updated, "updated"
+	}
+
+	attachments, thumbnail := mediaAttachments(e.MediaRss)
+
+	return model.Item{
+		Title:        e.Title.String(),
+		Description:  e.Summary.HTML(),
+		Content:      e.Content.HTML(),
+		PubDate:      parseDate(date, dateName, w),
+		Link:         alternateAtomLink(e.Links),
+		Guid:         model.Guid{Value: strings.TrimSpace(e.Id), IsPermaLink: false},
+		Author:       joinAtomPersons(e.Authors),
+		Enclosures:   atomEnclosures(e.Links),
+		Attachments:  attachments,
+		ThumbnailUrl: thumbnail,
+		Categories:   atomCategories(e.Categories),
+		XmlBase:      strings.TrimSpace(e.XmlBase),
+	}
+}
+
+func atomCategories(categories []atomCategory) []model.Category {
+	converted := make([]model.Category, 0, len(categories))
+
+	for _, c := range categories {
+		name := c.Term
+		if strings.TrimSpace(name) == "" {
+			name = c.Label
+		}
+
+		converted = append(converted, model.Category{Name: name, Domain: c.Scheme})
+	}
+
+	return normalizeCategories(converted)
+}
+
+// atomEnclosures returns the links with rel="enclosure", which is how Atom
+// attaches media files.
+func atomEnclosures(links []atomLink) []model.Enclosure {
+	var enclosures []model.Enclosure
+
+	for _, l := range links {
+		if l.Rel != "enclosure" {
+			continue
+		}
+
+		if e := newEnclosure(l.Href, l.Type, l.Length); e.Url != "" {
+			enclosures = append(enclosures, e)
+		}
+	}
+
+	return enclosures
+}
+
+// alternateAtomLink returns the link to the HTML version of an entry. A link
+// without a rel attribute is an alternate link by definition.
+func alternateAtomLink(links []atomLink) string {
+	for _, l := range links {
+		if l.Rel == "" || l.Rel == "alternate" {
+			return strings.TrimSpace(l.Href)
+		}
+	}
+
+	return ""
+}
+
+func joinAtomPersons(persons []atomPerson) string {
+	names := make([]string, 0, len(persons))
+
+	for _, p := range persons {
+		if name := strings.TrimSpace(p.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,288 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+const githubReleasesAtom = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/" xml:lang="en-US">
+  <id>tag:github.com,2008:https://github.com/golang/go/releases</id>
+  <link type="text/html" rel="alternate" href="https://github.com/golang/go/releases"/>
+  <link type="application/atom+xml" rel="self" href="https://github.com/golang/go/releases.atom"/>
+  <title>Release notes from go</title>
+  <updated>2025-07-08T17:03:51Z</updated>
+  <entry>
+    <id>tag:github.com,2008:Repository/23096959/go1.24.5</id>
+    <updated>2025-07-08T17:03:51Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/golang/go/releases/tag/go1.24.5"/>
+    <title>go1.24.5</title>
+    <content type="html">&lt;p&gt;[release-branch.go1.24] go1.24.5&lt;/p&gt;</content>
+    <author>
+      <name>gopherbot</name>
+    </author>
+    <media:thumbnail height="30" width="30" url="https://avatars.githubusercontent.com/u/8566911?s=60&amp;v=4"/>
+  </entry>
+  <entry>
+    <id>tag:github.com,2008:Repository/23096959/go1.23.11</id>
+    <updated>2025-07-08T17:03:25Z</updated>
+    <link rel="alternate" type="text/html" href="https://github.com/golang/go/releases/tag/go1.23.11"/>
+    <title>go1.23.11</title>
+    <content type="html">&lt;p&gt;[release-branch.go1.23] go1.23.11&lt;/p&gt;</content>
+    <author>
+      <name>gopherbot</name>
+    </author>
+  </entry>
+</feed>`
+
+const bloggerAtom = `<?xml version='1.0' encoding='UTF-8'?>
+<feed xmlns='http://www.w3.org/2005/Atom' xmlns:openSearch='http://a9.com/-/spec/opensearchrss/1.0/'
+      xmlns:blogger='http://schemas.google.com/blogger/2008'>
+  <id>tag:blogger.com,1999:blog-2471378914199150966</id>
+  <updated>2025-07-24T09:14:32.181-07:00</updated>
+  <title type='text'>Google Developers Blog</title>
+  <subtitle type='html'>News and insights on Google platforms, tools &amp;amp; events.</subtitle>
+  <link rel='alternate' type='text/html' href='https://developers.googleblog.com/'/>
+  <author><name>Google Developers</name></author>
+  <openSearch:totalResults>2</openSearch:totalResults>
+  <entry>
+    <id>tag:blogger.com,1999:blog-2471378914199150966.post-1</id>
+    <published>2025-07-24T09:00:00.000-07:00</published>
+    <updated>2025-07-24T09:14:32.181-07:00</updated>
+    <category scheme='http://www.blogger.com/atom/ns#' term='AI'/>
+    <title type='text'>Announcing the Agent Development Kit</title>
+    <summary type='text'>A short teaser &lt;not markup&gt;</summary>
+    <link rel='alternate' type='text/html' href='https://developers.googleblog.com/2025/07/adk.html'/>
+  </entry>
+  <entry>
+    <id>tag:blogger.com,1999:blog-2471378914199150966.post-2</id>
+    <published>2025-07-23T10:30:00.000-07:00</published>
+    <updated>2025-07-23T10:30:00.000-07:00</updated>
+    <title type='text'>Gemma on every device</title>
+    <content type='xhtml'><div xmlns='http://www.w3.org/1999/xhtml'><p>Gemma <b>everywhere</b></p></div></content>
+  </entry>
+</feed>`
+
+const youtubeAtom = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/"
+      xmlns="http://www.w3.org/2005/Atom">
+ <link rel="self" href="http://www.youtube.com/feeds/videos.xml?channel_id=UC_x5XG1OV2P6uZZ5FSM9Ttw"/>
+ <id>yt:channel:_x5XG1OV2P6uZZ5FSM9Ttw</id>
+ <yt:channelId>_x5XG1OV2P6uZZ5FSM9Ttw</yt:channelId>
+ <title>Google for Developers</title>
+ <link rel="alternate" href="https://www.youtube.com/channel/UC_x5XG1OV2P6uZZ5FSM9Ttw"/>
+ <published>2007-08-23T00:34:43+00:00</published>
+ <entry>
+  <id>yt:video:dQw4w9WgXcQ</id>
+  <yt:videoId>dQw4w9WgXcQ</yt:videoId>
+  <title>What's new in Go</title>
+  <link rel="alternate" href="https://www.youtube.com/watch?v=dQw4w9WgXcQ"/>
+  <published>2025-07-25T16:00:06+00:00</published>
+  <updated>2025-07-26T02:11:41+00:00</updated>
+  <media:group>
+   <media:title>What's new in Go</media:title>
+   <media:content url="https://www.youtube.com/v/dQw4w9WgXcQ?version=3" type="application/x-shockwave-flash"
+                  width="640" height="390"/>
+   <media:thumbnail url="https://i2.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg" width="480" height="360"/>
+   <media:description>Learn about the latest Go release.</media:description>
+  </media:group>
+ </entry>
+</feed>`
+
+func TestParse_Atom(t *testing.T) {
+	t.Run("GithubReleases", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(githubReleasesAtom))
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Release notes from go", channel.Title)
+		require.Equal(t, "en-US", channel.Language)
+		require.Equal(t, "https://github.com/golang/go/releases", channel.Link)
+		require.True(t, time.Time(channel.LastBuildDate).Equal(time.Date(2025, 7, 8, 17, 3, 51, 0, time.UTC)))
+		require.Len(t, channel.Items, 2)
+
+		item := channel.Items[0]
+		require.Equal(t, "go1.24.5", item.Title)
+		require.Empty(t, item.Description)
+		require.Equal(t, "<p>[release-branch.go1.24] go1.24.5</p>", item.Content)
+		require.Equal(t, "gopherbot", item.Author)
+		require.Equal(t, "https://github.com/golang/go/releases/tag/go1.24.5", item.Link)
+		require.Equal(t, "tag:github.com,2008:Repository/23096959/go1.24.5", item.Guid.Value)
+		require.False(t, item.Guid.IsPermaLink)
+		require.True(t, time.Time(item.PubDate).Equal(time.Date(2025, 7, 8, 17, 3, 51, 0, time.UTC)))
+
+		require.Equal(t, "https://avatars.githubusercontent.com/u/8566911?s=60&v=4", item.ThumbnailUrl)
+		require.Empty(t, item.Attachments)
+
+		require.Equal(t, "go1.23.11", channel.Items[1].Title)
+	})
+
+	t.Run("Blogger", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(bloggerAtom))
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Google Developers Blog", channel.Title)
+		require.Equal(t, "News and insights on Google platforms, tools & events.", channel.Description)
+		require.Len(t, channel.Items, 2)
+
+		item := channel.Items[0]
+		require.Equal(t, "Announcing the Agent Development Kit", item.Title)
+		require.Equal(t, "A short teaser &lt;not markup&gt;", item.Description)
+		require.Empty(t, item.Content)
+		require.Equal(t, "Google Developers", item.Author)
+		require.True(t, time.Time(item.PubDate).Equal(
+			time.Date(2025, 7, 24, 9, 0, 0, 0, time.FixedZone("UTC-7", -7*60*60))))
+
+		item = channel.Items[1]
+		require.Equal(t, "Gemma on every device", item.Title)
+		require.Equal(t, "<p>Gemma <b>everywhere</b></p>", item.Content)
+	})
+
+	t.Run("Youtube", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(youtubeAtom))
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Google for Developers", channel.Title)
+		require.Len(t, channel.Items, 1)
+
+		item := channel.Items[0]
+		require.Equal(t, "What's new in Go", item.Title)
+		require.Equal(t, "https://www.youtube.com/watch?v=dQw4w9WgXcQ", item.Link)
+		require.Equal(t, "yt:video:dQw4w9WgXcQ", item.Guid.Value)
+		require.True(t, time.Time(item.PubDate).Equal(time.Date(2025, 7, 25, 16, 0, 6, 0, time.UTC)))
+		require.Equal(t, "https://i2.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg", item.ThumbnailUrl)
+		require.Equal(t, []model.Attachment{{
+			Url:          "https://www.youtube.com/v/dQw4w9WgXcQ?version=3",
+			Type:         "application/x-shockwave-flash",
+			Width:        640,
+			Height:       390,
+			Description:  "Learn about the latest Go release.",
+			ThumbnailUrl: "https://i2.ytimg.com/vi/dQw4w9WgXcQ/hqdefault.jpg",
+		}}, item.Attachments)
+	})
+
+	t.Run("Enclosure", func(t *testing.T) {
+		const podcastAtom = `
+			<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Podcast</title>
+				<entry>
+					<title>Episode</title>
+					<link href="https://example.com/episode"/>
+					<link rel="enclosure" type="audio/mpeg" length="1337" href="https://example.com/episode.mp3"/>
+				</entry>
+			</feed>`
+
+		rss, err := Parser{}.Parse([]byte(podcastAtom))
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, "https://example.com/episode", item.Link)
+		require.Equal(t, []model.Enclosure{
+			{Url: "https://example.com/episode.mp3", Type: "audio/mpeg", Length: 1337},
+		}, item.Enclosures)
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		const categoriesAtom = `
+			<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Feed</title>
+				<category term="tech"/>
+				<entry>
+					<title>Entry</title>
+					<category term="golang" scheme="https://example.com/tags" label="Go"/>
+					<category label="Release notes"/>
+					<category term=" "/>
+				</entry>
+			</feed>`
+
+		rss, err := Parser{}.Parse([]byte(categoriesAtom))
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, []model.Category{{Name: "tech"}}, channel.Categories)
+		require.Equal(t, []model.Category{
+			{Name: "golang", Domain: "https://example.com/tags"},
+			{Name: "Release notes"},
+		}, channel.Items[0].Categories)
+	})
+
+	t.Run("XmlBase", func(t *testing.T) {
+		const xmlBaseAtom = `
+			<feed xmlns="http://www.w3.org/2005/Atom" xml:base="https://example.com/blog/">
+				<title>Feed</title>
+				<entry xml:base=" posts/1/ ">
+					<title>Entry</title>
+					<link href="index.html"/>
+				</entry>
+			</feed>`
+
+		rss, err := Parser{}.Parse([]byte(xmlBaseAtom))
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "https://example.com/blog/", channel.XmlBase)
+		require.Equal(t, "posts/1/", channel.Items[0].XmlBase)
+		// URLs are resolved on import, not by the parser.
+		require.Equal(t, "index.html", channel.Items[0].Link)
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		const badDateAtom = `
+			<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Feed</title>
+				<entry>
+					<title>Entry</title>
+					<updated>yesterday</updated>
+				</entry>
+			</feed>`
+
+		rss, err := Parser{}.Parse([]byte(badDateAtom))
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, "Entry", item.Title)
+		require.True(t, time.Time(item.PubDate).IsZero())
+	})
+
+	t.Run("Recovery", func(t *testing.T) {
+		const malformedAtom = `
+			<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Tom &amp; Jerry&rsquo;s blog</title>
+				<entry>
+					<title>Fish & chips</title>
+				</entry>
+			</feed>`
+
+		rss, err := Parser{}.Parse([]byte(malformedAtom))
+
+		require.NoError(t, err)
+		require.Equal(t, "Tom & Jerry\u2019s blog", rss.Channels[0].Title)
+		require.Equal(t, "Fish & chips", rss.Channels[0].Items[0].Title)
+		require.NotEmpty(t, rss.Warnings)
+	})
+
+	t.Run("InvalidXml", func(t *testing.T) {
+		invalidXml := []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Invalid`)
+
+		rss, err := Parser{}.Parse(invalidXml)
+
+		require.Error(t, err)
+		require.Empty(t, rss.Channels)
+	})
+}
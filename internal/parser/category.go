@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// normalizeCategories trims the categories of a channel or an item and drops
+// the empty ones and the repeated ones, which differ in case at most.
+func normalizeCategories(categories []model.Category) []model.Category {
+	var normalized []model.Category
+
+	for _, c := range categories {
+		c = model.Category{Name: strings.TrimSpace(c.Name), Domain: strings.TrimSpace(c.Domain)}
+		if c.Name == "" || containsCategory(normalized, c) {
+			continue
+		}
+
+		normalized = append(normalized, c)
+	}
+
+	return normalized
+}
+
+func containsCategory(categories []model.Category, c model.Category) bool {
+	for _, other := range categories {
+		if strings.EqualFold(other.Name, c.Name) && other.Domain == c.Domain {
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedCategories turns plain category names, such as JSON Feed tags, into
+// categories.
+func namedCategories(names []string) []model.Category {
+	categories := make([]model.Category, 0, len(names))
+
+	for _, name := range names {
+		categories = append(categories, model.Category{Name: name})
+	}
+
+	return normalizeCategories(categories)
+}
@@ -0,0 +1,69 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_LegacyCharsets(t *testing.T) {
+	t.Run("Windows1251", func(t *testing.T) {
+		bs, err := os.ReadFile(filepath.Join("testdata", "windows-1251.xml"))
+		require.NoError(t, err)
+
+		rss, err := Parser{}.Parse(bs)
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Новости технологий", channel.Title)
+		require.Equal(t, "Свежие новости из мира IT", channel.Description)
+		require.Len(t, channel.Items, 1)
+
+		item := channel.Items[0]
+		require.Equal(t, "Вышел Go 1.25", item.Title)
+		require.Equal(t, "Новая версия языка программирования Go.", item.Description)
+		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
+	})
+
+	t.Run("Latin1", func(t *testing.T) {
+		bs, err := os.ReadFile(filepath.Join("testdata", "iso-8859-1.xml"))
+		require.NoError(t, err)
+
+		rss, err := Parser{}.Parse(bs)
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Le Café Numérique", channel.Title)
+		require.Equal(t, "Actualités, critiques et réflexions", channel.Description)
+
+		item := channel.Items[0]
+		require.Equal(t, "Über die Zukunft des Journalismus", item.Title)
+		require.Equal(t, "Année après année, la presse évolue. ¿Qué pasará mañana?", item.Description)
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		// "Привет" in KOI8-R
+		atom := []byte("<?xml version=\"1.0\" encoding=\"KOI8-R\"?>" +
+			"<feed xmlns=\"http://www.w3.org/2005/Atom\"><title>\xf0\xd2\xc9\xd7\xc5\xd4</title></feed>")
+
+		rss, err := Parser{}.Parse(atom)
+
+		require.NoError(t, err)
+		require.Equal(t, "Привет", rss.Channels[0].Title)
+	})
+
+	t.Run("UnsupportedCharset", func(t *testing.T) {
+		xmlData := []byte(`<?xml version="1.0" encoding="x-unknown"?><rss><channel><title>T</title></channel></rss>`)
+
+		_, err := Parser{}.Parse(xmlData)
+
+		require.ErrorContains(t, err, "unsupported charset")
+	})
+}
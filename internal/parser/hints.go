@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// normalizeHints cleans up the polling hints of a channel: invalid hours and
+// days are dropped, days get their canonical English names and the update
+// period is lowercased. An invalid ttl is dropped with a warning.
+func normalizeHints(channel *model.Channel, w *warnings) {
+	channel.Ttl = parseTtl(channel.RawTtl, w)
+	channel.SkipHours = parseSkipHours(channel.RawSkipHours)
+	channel.SkipDays = parseSkipDays(channel.SkipDays)
+	channel.UpdatePeriod = parseUpdatePeriod(channel.UpdatePeriod)
+	channel.UpdateFrequency = parseUpdateFrequency(channel.RawUpdateFrequency)
+}
+
+func parseTtl(raw string, w *warnings) int {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+
+	ttl, err := strconv.Atoi(raw)
+	if err != nil || ttl < 0 {
+		w.add("ignoring <ttl>: invalid number of minutes %q", raw)
+
+		return 0
+	}
+
+	return ttl
+}
+
+func parseSkipHours(raw []string) []int {
+	const hoursPerDay = 24
+
+	var hours []int
+
+	for _, r := range raw {
+		hour, err := strconv.Atoi(strings.TrimSpace(r))
+		if err != nil || hour < 0 || hour > hoursPerDay {
+			continue
+		}
+
+		// Some publishers count hours from 1 to 24.
+		hours = append(hours, hour%hoursPerDay)
+	}
+
+	return hours
+}
+
+func parseSkipDays(raw []string) []string {
+	var days []string
+
+	for _, r := range raw {
+		r = strings.TrimSpace(r)
+
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.EqualFold(r, d.String()) {
+				days = append(days, d.String())
+
+				break
+			}
+		}
+	}
+
+	return days
+}
+
+func parseUpdatePeriod(raw string) string {
+	switch period := strings.ToLower(strings.TrimSpace(raw)); period {
+	case "hourly", "daily", "weekly", "monthly", "yearly":
+		return period
+	}
+
+	return ""
+}
+
+func parseUpdateFrequency(raw string) int {
+	frequency, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || frequency < 0 {
+		return 0
+	}
+
+	return frequency
+}
@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// jsonFeed covers both JSON Feed 1.0 and 1.1 (https://www.jsonfeed.org/version/1.1/).
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageUrl string         `json:"home_page_url"`
+	Icon        string         `json:"icon"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Author      *jsonAuthor    `json:"author"`
+	Authors     []jsonAuthor   `json:"authors"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	Id            jsonFeedId       `json:"id"`
+	Url           string           `json:"url"`
+	ExternalUrl   string           `json:"external_url"`
+	Title         string           `json:"title"`
+	ContentHtml   string           `json:"content_html"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Author        *jsonAuthor      `json:"author"`
+	Authors       []jsonAuthor     `json:"authors"`
+	Attachments   []jsonAttachment `json:"attachments"`
+	Tags          []string         `json:"tags"`
+}
+
+type jsonAttachment struct {
+	Url               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	SizeInBytes       float64 `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
+type jsonAuthor struct {
+	Name string `json:"name"`
+}
+
+// jsonFeedId is a string by the spec, but numbers are common in the wild.
+type jsonFeedId string
+
+func (id *jsonFeedId) UnmarshalJSON(bs []byte) error {
+	var v any
+	if err := json.Unmarshal(bs, &v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case string:
+		*id = jsonFeedId(v)
+	case float64:
+		*id = jsonFeedId(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+
+	return nil
+}
+
+func parseJsonFeed(bs []byte) (model.Rss, error) {
+	var feed jsonFeed
+
+	if err := json.Unmarshal(bs, &feed); err != nil {
+		return model.Rss{}, fmt.Errorf("failed unmarshalling json feed data: %w", err)
+	}
+
+	if !strings.Contains(feed.Version, "jsonfeed.org/version/") {
+		return model.Rss{}, fmt.Errorf("unsupported json feed version: %q", feed.Version)
+	}
+
+	channel := model.Channel{
+		Title:       feed.Title,
+		Language:    feed.Language,
+		Description: feed.Description,
+		Link:        feed.HomePageUrl,
+		ImageUrl:    feed.Icon,
+		Items:       make([]model.Item, 0, len(feed.Items)),
+	}
+
+	feedAuthor := joinJsonAuthors(feed.Authors, feed.Author)
+
+	var w warnings
+
+	for i := range feed.Items {
+		item := feed.Items[i].toItem(&w)
+
+		if item.Author == "" {
+			item.Author = feedAuthor
+		}
+
+		channel.Items = append(channel.Items, item)
+	}
+
+	return model.Rss{Channels: []model.Channel{channel}, Warnings: w}, nil
+}
+
+func (i *jsonFeedItem) toItem(w *warnings) model.Item {
+	content := i.ContentHtml
+	if content == "" {
+		content = html.EscapeString(i.ContentText)
+	}
+
+	date, dateName := i.DatePublished, "date_published"
+	if strings.TrimSpace(date) == "" {
+		date, dateName = i.DateModified, "date_modified"
+	}
+
+	link := i.Url
+	if link == "" {
+		link = i.ExternalUrl
+	}
+
+	item := model.Item{
+		Title:       i.Title,
+		Description: html.EscapeString(i.Summary),
+		Content:     content,
+		Categories:  namedCategories(i.Tags),
+		PubDate:     parseDate(date, dateName, w),
+		Link:        link,
+		Guid:        model.Guid{Value: string(i.Id), IsPermaLink: false},
+		Author:      joinJsonAuthors(i.Authors, i.Author),
+	}
+
+	for _, a := range i.Attachments {
+		url := strings.TrimSpace(a.Url)
+		if url == "" {
+			continue
+		}
+
+		item.Enclosures = append(item.Enclosures, model.Enclosure{
+			Url:    url,
+			Type:   strings.TrimSpace(a.MimeType),
+			Length: max(int64(a.SizeInBytes), 0),
+		})
+
+		if item.Duration == 0 {
+			item.Duration = max(int(a.DurationInSeconds), 0)
+		}
+	}
+
+	return item
+}
+
+// joinJsonAuthors prefers the 1.1 "authors" array and falls back to the
+// deprecated 1.0 "author" object.
+func joinJsonAuthors(authors []jsonAuthor, legacy *jsonAuthor) string {
+	if len(authors) == 0 && legacy != nil {
+		authors = []jsonAuthor{*legacy}
+	}
+
+	names := make([]string, 0, len(authors))
+
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
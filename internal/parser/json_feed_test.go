@@ -0,0 +1,145 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+func TestParse_JsonFeed(t *testing.T) {
+	t.Run("Version1_1", func(t *testing.T) {
+		jsonData := []byte(`
+			{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "My Newsletter",
+				"home_page_url": "https://example.org/",
+				"feed_url": "https://example.org/feed.json",
+				"description": "Weekly notes",
+				"language": "en",
+				"authors": [{"name": "Jane Doe"}],
+				"items": [
+					{
+						"id": "2",
+						"url": "https://example.org/second-item",
+						"title": "Second item",
+						"content_html": "<p>Hello, <b>world</b>!</p>",
+						"tags": ["golang", " Golang ", ""],
+						"date_published": "2025-07-27T13:45:00+03:00",
+						"authors": [{"name": "John Roe"}, {"name": "Jane Doe"}]
+					},
+					{
+						"id": "1",
+						"title": "First item",
+						"content_text": "Plain <text>",
+						"date_modified": "2025-07-27T10:45:00Z"
+					}
+				]
+			}`)
+
+		rss, err := Parser{}.Parse(jsonData)
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "My Newsletter", channel.Title)
+		require.Equal(t, "en", channel.Language)
+		require.Equal(t, "Weekly notes", channel.Description)
+		require.Equal(t, "https://example.org/", channel.Link)
+		require.Len(t, channel.Items, 2)
+
+		item := channel.Items[0]
+		require.Equal(t, "Second item", item.Title)
+		require.Equal(t, "<p>Hello, <b>world</b>!</p>", item.Content)
+		require.Equal(t, []model.Category{{Name: "golang"}}, item.Categories)
+		require.Equal(t, "John Roe, Jane Doe", item.Author)
+		require.Equal(t, "https://example.org/second-item", item.Link)
+		require.Equal(t, "2", item.Guid.Value)
+		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
+
+		item = channel.Items[1]
+		require.Equal(t, "First item", item.Title)
+		require.Equal(t, "Plain &lt;text&gt;", item.Content)
+		require.Equal(t, "Jane Doe", item.Author)
+		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
+	})
+
+	t.Run("Version1LegacyAuthor", func(t *testing.T) {
+		jsonData := []byte(`
+			{
+				"version": "https://jsonfeed.org/version/1",
+				"title": "Legacy",
+				"items": [
+					{
+						"id": 1,
+						"external_url": "https://example.org/elsewhere",
+						"title": "Item",
+						"summary": "Summary",
+						"author": {"name": "Legacy Author"}
+					}
+				]
+			}`)
+
+		rss, err := Parser{}.Parse(jsonData)
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, "Summary", item.Description)
+		require.Empty(t, item.Content)
+		require.Equal(t, "Legacy Author", item.Author)
+		require.Equal(t, "https://example.org/elsewhere", item.Link)
+		require.Equal(t, "1", item.Guid.Value)
+		require.True(t, time.Time(item.PubDate).IsZero())
+	})
+
+	t.Run("Attachments", func(t *testing.T) {
+		jsonData := []byte(`
+			{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "Podcast",
+				"items": [
+					{
+						"id": "1",
+						"title": "Episode",
+						"attachments": [
+							{
+								"url": "https://example.org/1.m4a",
+								"mime_type": "audio/x-m4a",
+								"size_in_bytes": 89970236,
+								"duration_in_seconds": 6629
+							},
+							{"url": ""}
+						]
+					}
+				]
+			}`)
+
+		rss, err := Parser{}.Parse(jsonData)
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, []model.Enclosure{
+			{Url: "https://example.org/1.m4a", Type: "audio/x-m4a", Length: 89970236},
+		}, item.Enclosures)
+		require.Equal(t, 6629, item.Duration)
+	})
+
+	t.Run("UnknownVersion", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(`{"title": "Not a feed"}`))
+
+		require.Error(t, err)
+		require.Empty(t, rss.Channels)
+	})
+
+	t.Run("InvalidJson", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(`{"version": "https://jsonfeed.org/version/1.1", "items": [`))
+
+		require.Error(t, err)
+		require.Empty(t, rss.Channels)
+	})
+}
@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// mediaAttachments turns the Media RSS elements of an item into attachments
+// and picks the thumbnail of the item. Descriptions, credits and thumbnails
+// are inherited from the enclosing group and item, as the spec requires.
+func mediaAttachments(m model.MediaRss) ([]model.Attachment, string) {
+	itemThumbnail := firstThumbnail(m.MediaThumbnails)
+	itemCredit := joinCredits(m.MediaCredits)
+
+	var attachments []model.Attachment
+
+	add := func(c model.MediaContent, description, credit, thumbnail string) {
+		if a := newAttachment(c, description, credit, thumbnail); a.Url != "" {
+			attachments = append(attachments, a)
+		}
+	}
+
+	for _, c := range m.MediaContents {
+		add(c, "", itemCredit, itemThumbnail)
+	}
+
+	thumbnail := itemThumbnail
+
+	for _, g := range m.MediaGroups {
+		groupThumbnail := firstNonEmpty(firstThumbnail(g.Thumbnails), itemThumbnail)
+		groupCredit := firstNonEmpty(joinCredits(g.Credits), itemCredit)
+
+		thumbnail = firstNonEmpty(thumbnail, groupThumbnail)
+
+		for _, c := range g.Contents {
+			add(c, strings.TrimSpace(g.Description), groupCredit, groupThumbnail)
+		}
+	}
+
+	for _, a := range attachments {
+		thumbnail = firstNonEmpty(thumbnail, a.ThumbnailUrl)
+	}
+
+	// An image is its own thumbnail.
+	for _, a := range attachments {
+		if a.Medium == "image" || strings.HasPrefix(a.Type, "image/") {
+			thumbnail = firstNonEmpty(thumbnail, a.Url)
+		}
+	}
+
+	return attachments, thumbnail
+}
+
+func newAttachment(c model.MediaContent, description, credit, thumbnail string) model.Attachment {
+	mediaType, _, err := mime.ParseMediaType(c.Type)
+	if err != nil {
+		mediaType = ""
+	}
+
+	fileSize, err := strconv.ParseInt(strings.TrimSpace(c.FileSize), 10, 64)
+	if err != nil || fileSize < 0 {
+		fileSize = 0
+	}
+
+	return model.Attachment{
+		Url:          strings.TrimSpace(c.Url),
+		Type:         mediaType,
+		Medium:       strings.ToLower(strings.TrimSpace(c.Medium)),
+		Width:        parseCount(c.Width),
+		Height:       parseCount(c.Height),
+		FileSize:     fileSize,
+		Duration:     parseCount(c.Duration),
+		Description:  firstNonEmpty(strings.TrimSpace(c.Description), description),
+		Credit:       firstNonEmpty(joinCredits(c.Credits), credit),
+		ThumbnailUrl: firstNonEmpty(firstThumbnail(c.Thumbnails), thumbnail),
+	}
+}
+
+func firstThumbnail(thumbnails []model.MediaThumbnail) string {
+	for _, t := range thumbnails {
+		if url := strings.TrimSpace(t.Url); url != "" {
+			return url
+		}
+	}
+
+	return ""
+}
+
+func joinCredits(credits []string) string {
+	names := make([]string, 0, len(credits))
+
+	for _, c := range credits {
+		if c = strings.TrimSpace(c); c != "" {
+			names = append(names, c)
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+
+	return ""
+}
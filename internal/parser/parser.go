@@ -1,20 +1,233 @@
 package parser
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
 	"github.com/marchuknikolay/rss-parser/internal/model"
 )
 
+const (
+	atomNamespace = "http://www.w3.org/2005/Atom"
+	rdfNamespace  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+)
+
+const utf8Bom = "\xEF\xBB\xBF"
+
+type format int
+
+const (
+	formatRss format = iota
+	formatAtom
+	formatJson
+	formatRdf
+)
+
+// Names of the feed formats, as reported by FormatName.
+const (
+	FormatRss  = "RSS"
+	FormatAtom = "Atom"
+	FormatJson = "JSON Feed"
+	FormatRdf  = "RSS 1.0 (RDF)"
+)
+
+func (f format) String() string {
+	switch f {
+	case formatAtom:
+		return FormatAtom
+	case formatJson:
+		return FormatJson
+	case formatRdf:
+		return FormatRdf
+	default:
+		return FormatRss
+	}
+}
+
+// FormatName tells which format Parse decodes the document as.
+func FormatName(bs []byte) string {
+	return detectFormat(bs).String()
+}
+
 type Parser struct{}
 
+// warnings collects what was dropped or worked around while decoding a feed,
+// so that a single bad value doesn't have to fail the whole feed.
+type warnings []string
+
+func (w *warnings) add(format string, args ...any) {
+	*w = append(*w, fmt.Sprintf(format, args...))
+}
+
+// Parse decodes an RSS, Atom, RSS 1.0 or JSON feed. XML feeds that are not
+// well-formed are repaired and decoded leniently, with the repairs reported
+// in the Warnings of the result.
 func (Parser) Parse(bs []byte) (model.Rss, error) {
-	var rss model.Rss
+	rss, err := parse(bs, false)
+
+	var syntaxErr *xml.SyntaxError
+	if err == nil || !errors.As(err, &syntaxErr) {
+		return rss, err
+	}
 
-	if err := xml.Unmarshal(bs, &rss); err != nil {
+	return parseRepaired(bs, err)
+}
+
+func parse(bs []byte, lenient bool) (model.Rss, error) {
+	switch detectFormat(bs) {
+	case formatAtom:
+		return parseAtom(bs, lenient)
+	case formatJson:
+		return parseJsonFeed(bs)
+	case formatRdf:
+		return parseRdf(bs, lenient)
+	default:
+		return parseRss(bs, lenient)
+	}
+}
+
+func parseRss(bs []byte, lenient bool) (model.Rss, error) {
+	var (
+		rss model.Rss
+		w   warnings
+	)
+
+	if err := unmarshalXml(bs, &rss, lenient); err != nil {
 		return model.Rss{}, fmt.Errorf("failed unmarshalling xml data: %w", err)
 	}
 
+	for i := range rss.Channels {
+		normalizeRssChannel(&rss.Channels[i], &w)
+
+		for j := range rss.Channels[i].Items {
+			normalizeRssItem(&rss.Channels[i].Items[j], &w)
+		}
+	}
+
+	rss.Warnings = w
+
 	return rss, nil
 }
+
+// unmarshalXml is xml.Unmarshal for documents in any encoding supported by
+// decoder.CharsetReader. A lenient decoder keeps unknown entities as text and
+// understands the HTML ones, such as &nbsp;.
+func unmarshalXml(bs []byte, v any, lenient bool) error {
+	d := newXmlDecoder(bs)
+
+	if lenient {
+		d.Strict = false
+		d.Entity = xml.HTMLEntity
+	}
+
+	return d.Decode(v)
+}
+
+func newXmlDecoder(bs []byte) *xml.Decoder {
+	d := xml.NewDecoder(bytes.NewReader(bs))
+	d.CharsetReader = decoder.CharsetReader
+
+	return d
+}
+
+func normalizeRssChannel(channel *model.Channel, w *warnings) {
+	for _, link := range channel.Links {
+		if link = strings.TrimSpace(link); link != "" {
+			channel.Link = link
+
+			break
+		}
+	}
+
+	channel.ImageUrl = strings.TrimSpace(channel.ImageUrl)
+	channel.LastBuildDate = parseDate(channel.RawLastBuildDate, "lastBuildDate", w)
+	channel.Generator = strings.TrimSpace(channel.Generator)
+	channel.Categories = normalizeCategories(channel.Categories)
+
+	normalizeHints(channel, w)
+}
+
+// normalizeRssItem fills in the fields that RSS allows to be expressed in
+// more than one way.
+func normalizeRssItem(item *model.Item, w *warnings) {
+	item.PubDate = parseDate(item.RawPubDate, "pubDate", w)
+	item.Link = strings.TrimSpace(item.Link)
+	item.Author = strings.TrimSpace(item.Author)
+
+	if item.Author == "" {
+		item.Author = strings.TrimSpace(item.Creator)
+	}
+
+	if item.Link == "" && item.Guid.IsPermaLink && isHttpUrl(item.Guid.Value) {
+		item.Link = item.Guid.Value
+	}
+
+	item.Categories = normalizeCategories(item.Categories)
+
+	normalizePodcast(item)
+
+	item.Attachments, item.ThumbnailUrl = mediaAttachments(item.MediaRss)
+}
+
+func isHttpUrl(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
+
+// detectFormat looks at the root element of the document to choose a decoder.
+// Anything that is not recognized falls back to RSS, so malformed documents
+// are reported by the RSS decoder as before.
+func detectFormat(bs []byte) format {
+	if isJson(bs) {
+		return formatJson
+	}
+
+	d := newXmlDecoder(bs)
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return formatRss
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case se.Name.Local == "feed" && se.Name.Space == atomNamespace:
+			return formatAtom
+		case se.Name.Local == "RDF" && se.Name.Space == rdfNamespace:
+			return formatRdf
+		default:
+			return formatRss
+		}
+	}
+}
+
+// isJson reports whether the document is a JSON object rather than XML.
+func isJson(bs []byte) bool {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(bs, []byte(utf8Bom)))
+
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// parseDate parses any date format known to model.ParseDateTime. An
+// unparseable date is dropped with a warning naming the element it came from
+// rather than failing the whole feed.
+func parseDate(s, name string, w *warnings) model.DateTime {
+	if strings.TrimSpace(s) == "" {
+		return model.DateTime{}
+	}
+
+	dt, err := model.ParseDateTime(s)
+	if err != nil {
+		w.add("ignoring <%v>: %v", name, err)
+	}
+
+	return dt
+}
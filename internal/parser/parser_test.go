@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
 )
 
 var expectedDateTime = time.Date(2025, 7, 27, 13, 45, 0, 0, time.FixedZone("UTC+3", 3*60*60))
@@ -80,6 +82,376 @@ func TestParse(t *testing.T) {
 		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
 	})
 
+	t.Run("ChannelMetadata", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:atom="http://www.w3.org/2005/Atom">
+				<channel>
+					<title>Channel 1</title>
+					<atom:link href="https://example.com/feed.xml" rel="self" type="application/rss+xml"/>
+					<link>https://example.com/</link>
+					<image>
+						<url>https://example.com/logo.png</url>
+						<title>Channel 1</title>
+						<link>https://example.com/</link>
+					</image>
+					<lastBuildDate>Sat, 27 Jul 2025 13:45:00 +0300</lastBuildDate>
+					<ttl>60</ttl>
+					<generator>WordPress 6.8</generator>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "https://example.com/", channel.Link)
+		require.Equal(t, "https://example.com/logo.png", channel.ImageUrl)
+		require.True(t, time.Time(channel.LastBuildDate).Equal(expectedDateTime))
+		require.Equal(t, 60, channel.Ttl)
+		require.Equal(t, "WordPress 6.8", channel.Generator)
+	})
+
+	t.Run("PollingHints", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:sy="http://purl.org/rss/1.0/modules/syndication/">
+				<channel>
+					<title>Channel 1</title>
+					<ttl>120</ttl>
+					<skipHours><hour>0</hour><hour> 5 </hour><hour>24</hour><hour>noon</hour></skipHours>
+					<skipDays><day>saturday</day><day>Sunday</day><day>Someday</day></skipDays>
+					<sy:updatePeriod>weekly</sy:updatePeriod>
+					<sy:updateFrequency>two</sy:updateFrequency>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, 120, channel.Ttl)
+		require.Equal(t, []int{0, 5, 0}, channel.SkipHours)
+		require.Equal(t, []string{"Saturday", "Sunday"}, channel.SkipDays)
+		require.Equal(t, "weekly", channel.UpdatePeriod)
+		require.Zero(t, channel.UpdateFrequency)
+		require.Empty(t, rss.Warnings)
+	})
+
+	t.Run("InvalidTtl", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss>
+				<channel>
+					<title>Channel 1</title>
+					<ttl>one hour</ttl>
+					<item><title>Item 1</title></item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+		require.Zero(t, rss.Channels[0].Ttl)
+		require.Len(t, rss.Channels[0].Items, 1)
+		require.Equal(t, []string{`ignoring <ttl>: invalid number of minutes "one hour"`}, rss.Warnings)
+	})
+
+	t.Run("InvalidDates", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss>
+				<channel>
+					<title>Channel 1</title>
+					<lastBuildDate>yesterday</lastBuildDate>
+					<item><title>Item 1</title><pubDate>Invalid date</pubDate></item>
+					<item><title>Item 2</title><pubDate></pubDate></item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.True(t, channel.LastBuildDate.IsZero())
+		require.Equal(t, "Item 1", channel.Items[0].Title)
+		require.True(t, channel.Items[0].PubDate.IsZero())
+		require.True(t, channel.Items[1].PubDate.IsZero())
+		require.Equal(t, []string{
+			`ignoring <lastBuildDate>: unrecognized date format: "yesterday"`,
+			`ignoring <pubDate>: unrecognized date format: "Invalid date"`,
+		}, rss.Warnings)
+	})
+
+	t.Run("Categories", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+				<channel>
+					<title>Blog</title>
+					<category>Technology</category>
+					<itunes:category text="Technology">
+						<itunes:category text="Podcasting"/>
+					</itunes:category>
+					<item>
+						<title>Item</title>
+						<category domain="https://example.com/tags"> golang </category>
+						<category>Go</category>
+						<category>go</category>
+						<category></category>
+					</item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, []model.Category{{Name: "Technology"}}, channel.Categories)
+		require.Equal(t, []model.Category{
+			{Name: "golang", Domain: "https://example.com/tags"},
+			{Name: "Go"},
+		}, channel.Items[0].Categories)
+	})
+
+	t.Run("ContentEncoded", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:content="http://purl.org/rss/1.0/modules/content/">
+				<channel>
+					<title>Substack</title>
+					<item>
+						<title>Item</title>
+						<description>Short teaser</description>
+						<content:encoded><![CDATA[<p>The <b>full</b> article</p>]]></content:encoded>
+					</item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, "Short teaser", item.Description)
+		require.Equal(t, "<p>The <b>full</b> article</p>", item.Content)
+	})
+
+	t.Run("Podcast", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+				<channel>
+					<title>Go Time</title>
+					<item>
+						<title>Episode 1</title>
+						<enclosure url=" https://cdn.example.com/1.mp3 " length="24986239" type="audio/mpeg"/>
+						<enclosure url="https://cdn.example.com/1.mp4" length="unknown" type="video/mp4"/>
+						<enclosure url="" length="1" type="audio/mpeg"/>
+						<itunes:duration>1:02:03</itunes:duration>
+						<itunes:episode>1</itunes:episode>
+						<itunes:season>2</itunes:season>
+						<itunes:image href="https://cdn.example.com/1.jpg"/>
+						<itunes:explicit>yes</itunes:explicit>
+					</item>
+					<item>
+						<title>Episode 2</title>
+						<itunes:duration>not long</itunes:duration>
+						<itunes:episode>-1</itunes:episode>
+						<itunes:explicit>clean</itunes:explicit>
+					</item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, []model.Enclosure{
+			{Url: "https://cdn.example.com/1.mp3", Type: "audio/mpeg", Length: 24986239},
+			{Url: "https://cdn.example.com/1.mp4", Type: "video/mp4"},
+		}, item.Enclosures)
+		require.Equal(t, 3723, item.Duration)
+		require.Equal(t, 1, item.Episode)
+		require.Equal(t, 2, item.Season)
+		require.Equal(t, "https://cdn.example.com/1.jpg", item.ImageUrl)
+		require.True(t, item.Explicit)
+
+		item = rss.Channels[0].Items[1]
+		require.Nil(t, item.Enclosures)
+		require.Zero(t, item.Duration)
+		require.Zero(t, item.Episode)
+		require.False(t, item.Explicit)
+	})
+
+	t.Run("PodcastDuration", func(t *testing.T) {
+		tests := map[string]int{
+			"3600":     3600,
+			"45:30":    2730,
+			"01:02:03": 3723,
+			"90.5":     90,
+			"1:75":     0,
+			"1:2:3:4":  0,
+			"":         0,
+		}
+
+		for raw, expected := range tests {
+			require.Equal(t, expected, parseDuration(raw), raw)
+		}
+	})
+
+	t.Run("MediaRss", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:media="http://search.yahoo.com/mrss/">
+				<channel>
+					<title>Photos</title>
+					<item>
+						<title>Sunset</title>
+						<description>Item description</description>
+						<media:credit role="photographer">Jane Doe</media:credit>
+						<media:content url="https://example.com/sunset.jpg" medium="image" type="image/jpeg"
+							width="1024" height="wide" fileSize="204800">
+							<media:description>Sunset over the sea</media:description>
+						</media:content>
+					</item>
+					<item>
+						<title>News clip</title>
+						<media:group>
+							<media:description>Evening news</media:description>
+							<media:credit>Channel 5</media:credit>
+							<media:content url="https://example.com/clip-hd.mp4" type="video/mp4" duration="95"/>
+							<media:content url="https://example.com/clip-sd.mp4" type="video/mp4" duration="95"
+								medium="Video">
+								<media:thumbnail url="https://example.com/clip-sd.jpg"/>
+							</media:content>
+							<media:thumbnail url="https://example.com/clip.jpg"/>
+						</media:group>
+					</item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		item := rss.Channels[0].Items[0]
+		require.Equal(t, "Item description", item.Description)
+		require.Equal(t, "https://example.com/sunset.jpg", item.ThumbnailUrl)
+		require.Equal(t, []model.Attachment{{
+			Url:         "https://example.com/sunset.jpg",
+			Type:        "image/jpeg",
+			Medium:      "image",
+			Width:       1024,
+			FileSize:    204800,
+			Description: "Sunset over the sea",
+			Credit:      "Jane Doe",
+		}}, item.Attachments)
+
+		item = rss.Channels[0].Items[1]
+		require.Equal(t, "https://example.com/clip.jpg", item.ThumbnailUrl)
+		require.Equal(t, []model.Attachment{
+			{
+				Url:          "https://example.com/clip-hd.mp4",
+				Type:         "video/mp4",
+				Duration:     95,
+				Description:  "Evening news",
+				Credit:       "Channel 5",
+				ThumbnailUrl: "https://example.com/clip.jpg",
+			},
+			{
+				Url:          "https://example.com/clip-sd.mp4",
+				Type:         "video/mp4",
+				Medium:       "video",
+				Duration:     95,
+				Description:  "Evening news",
+				Credit:       "Channel 5",
+				ThumbnailUrl: "https://example.com/clip-sd.jpg",
+			},
+		}, item.Attachments)
+	})
+
+	t.Run("LinkGuidAuthor", func(t *testing.T) {
+		xmlData := []byte(`
+			<rss xmlns:dc="http://purl.org/dc/elements/1.1/">
+				<channel>
+					<title>Channel 1</title>
+					<item>
+						<title>Item 1</title>
+						<link>https://example.com/item-1</link>
+						<guid isPermaLink="false">item-1</guid>
+						<author>editor@example.com (Editor)</author>
+					</item>
+					<item>
+						<title>Item 2</title>
+						<guid>https://example.com/item-2</guid>
+						<dc:creator>Jane Doe</dc:creator>
+					</item>
+					<item>
+						<title>Item 3</title>
+						<guid isPermaLink="false">https://example.com/item-3</guid>
+					</item>
+				</channel>
+			</rss>`)
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		items := rss.Channels[0].Items
+
+		require.Equal(t, "https://example.com/item-1", items[0].Link)
+		require.Equal(t, model.Guid{Value: "item-1", IsPermaLink: false}, items[0].Guid)
+		require.Equal(t, "editor@example.com (Editor)", items[0].Author)
+
+		require.Equal(t, "https://example.com/item-2", items[1].Link)
+		require.Equal(t, model.Guid{Value: "https://example.com/item-2", IsPermaLink: true}, items[1].Guid)
+		require.Equal(t, "Jane Doe", items[1].Author)
+
+		require.Empty(t, items[2].Link)
+		require.Empty(t, items[2].Author)
+	})
+
+	t.Run("Recovery", func(t *testing.T) {
+		xmlData := []byte("<rss version=\"2.0\">\n" +
+			"<channel>\n" +
+			"<title>News &amp; Views</title>\n" +
+			"<item>\n" +
+			"<title>AT&T&nbsp;merger \x0bupdate</title>\n" +
+			"<description><![CDATA[Q&A &copy; 2025]]></description>\n" +
+			"<link>https://example.com/?a=1&b=2</link>\n" +
+			"<author>&bogus; &#169; &#xA9;</author>\n" +
+			"</item>\n" +
+			"</channel>\n" +
+			"</rss>")
+
+		rss, err := Parser{}.Parse(xmlData)
+
+		require.NoError(t, err)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "News & Views", channel.Title)
+
+		item := channel.Items[0]
+		require.Equal(t, "AT&T\u00a0merger update", item.Title)
+		require.Equal(t, "Q&A &copy; 2025", item.Description)
+		require.Equal(t, "https://example.com/?a=1&b=2", item.Link)
+		require.Equal(t, "&bogus; © ©", item.Author)
+
+		require.Len(t, rss.Warnings, 5)
+		require.Contains(t, rss.Warnings[0], "recovered from malformed XML")
+		require.Equal(t, []string{
+			"removed 1 invalid control characters",
+			"escaped 2 bare ampersands",
+			"decoded 1 HTML entities",
+			"kept 1 unknown entities as text",
+		}, rss.Warnings[1:])
+	})
+
+	t.Run("WellFormedHasNoWarnings", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(`<rss><channel><title>A &amp; B</title></channel></rss>`))
+
+		require.NoError(t, err)
+		require.Empty(t, rss.Warnings)
+	})
+
 	t.Run("InvalidXml", func(t *testing.T) {
 		invalidXml := []byte(`<rss><channel><title>Invalid`)
 
@@ -89,3 +461,23 @@ func TestParse(t *testing.T) {
 		require.Empty(t, rss.Channels)
 	})
 }
+
+func TestFormatName(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		expected string
+	}{
+		{"Rss", `<rss version="2.0"><channel/></rss>`, FormatRss},
+		{"Atom", `<feed xmlns="http://www.w3.org/2005/Atom"/>`, FormatAtom},
+		{"Rdf", `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"/>`, FormatRdf},
+		{"Json", `{"version": "https://jsonfeed.org/version/1.1"}`, FormatJson},
+		{"Unknown", `<html/>`, FormatRss},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, FormatName([]byte(tt.document)))
+		})
+	}
+}
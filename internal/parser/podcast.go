@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// normalizePodcast cleans up the enclosures and iTunes fields of an item.
+// Like polling hints, invalid values are dropped instead of failing the feed.
+func normalizePodcast(item *model.Item) {
+	enclosures := item.Enclosures[:0]
+
+	for _, e := range item.Enclosures {
+		if e = newEnclosure(e.Url, e.Type, e.RawLength); e.Url != "" {
+			enclosures = append(enclosures, e)
+		}
+	}
+
+	if len(enclosures) == 0 {
+		enclosures = nil
+	}
+
+	item.Enclosures = enclosures
+	item.Duration = parseDuration(item.RawDuration)
+	item.Episode = parseCount(item.RawEpisode)
+	item.Season = parseCount(item.RawSeason)
+	item.ImageUrl = strings.TrimSpace(item.RawImage.Href)
+	item.Explicit = parseExplicit(item.RawExplicit)
+}
+
+func newEnclosure(url, mediaType, rawLength string) model.Enclosure {
+	return model.Enclosure{
+		Url:    strings.TrimSpace(url),
+		Type:   strings.TrimSpace(mediaType),
+		Length: parseLength(rawLength),
+	}
+}
+
+func parseLength(raw string) int64 {
+	length, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	if err != nil || length < 0 {
+		return 0
+	}
+
+	return length
+}
+
+// parseDuration accepts the formats allowed by Apple: a number of seconds,
+// MM:SS or HH:MM:SS.
+func parseDuration(raw string) int {
+	const (
+		maxParts = 3
+		base     = 60
+	)
+
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+
+	parts := strings.Split(raw, ":")
+	if len(parts) > maxParts {
+		return 0
+	}
+
+	seconds := 0
+
+	for i, p := range parts {
+		// Fractions of a second show up in the wild.
+		if i == len(parts)-1 {
+			p, _, _ = strings.Cut(p, ".")
+		}
+
+		n, err := strconv.Atoi(strings.TrimSpace(p))
+		if err != nil || n < 0 || (i > 0 && n >= base) {
+			return 0
+		}
+
+		seconds = seconds*base + n
+	}
+
+	return seconds
+}
+
+func parseCount(raw string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// parseExplicit understands both the current true/false values and the
+// legacy yes/explicit/clean ones.
+func parseExplicit(raw string) bool {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case "true", "yes", "explicit":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,85 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// rdfFeed is an RSS 1.0 document. Unlike RSS 2.0, items are siblings of the
+// channel rather than its children.
+type rdfFeed struct {
+	Channel  rdfChannel `xml:"channel"`
+	ImageUrl string     `xml:"image>url"`
+	Items    []rdfItem  `xml:"item"`
+}
+
+type rdfChannel struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Description string `xml:"description"`
+	Language    string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	// RSS 1.0 feeds carry polling hints only via the syndication module.
+	UpdatePeriod    string `xml:"http://purl.org/rss/1.0/modules/syndication/ updatePeriod"`
+	UpdateFrequency string `xml:"http://purl.org/rss/1.0/modules/syndication/ updateFrequency"`
+}
+
+type rdfItem struct {
+	About       string `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Link        string `xml:"link"`
+	Title       string `xml:"title"`
+	Description string `xml:"description"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	// Subjects are the Dublin Core way to categorize an item.
+	Subjects []string `xml:"http://purl.org/dc/elements/1.1/ subject"`
+}
+
+func parseRdf(bs []byte, lenient bool) (model.Rss, error) {
+	var feed rdfFeed
+
+	if err := unmarshalXml(bs, &feed, lenient); err != nil {
+		return model.Rss{}, fmt.Errorf("failed unmarshalling rdf data: %w", err)
+	}
+
+	var w warnings
+
+	channel := feed.toChannel(&w)
+	channel.Items = make([]model.Item, 0, len(feed.Items))
+
+	for i := range feed.Items {
+		channel.Items = append(channel.Items, feed.Items[i].toItem(&w))
+	}
+
+	return model.Rss{Channels: []model.Channel{channel}, Warnings: w}, nil
+}
+
+// toChannel returns the channel metadata without items.
+func (f *rdfFeed) toChannel(w *warnings) model.Channel {
+	return model.Channel{
+		Title:           f.Channel.Title,
+		Language:        f.Channel.Language,
+		Description:     f.Channel.Description,
+		Link:            strings.TrimSpace(f.Channel.Link),
+		ImageUrl:        strings.TrimSpace(f.ImageUrl),
+		LastBuildDate:   parseDate(f.Channel.Date, "dc:date", w),
+		UpdatePeriod:    parseUpdatePeriod(f.Channel.UpdatePeriod),
+		UpdateFrequency: parseUpdateFrequency(f.Channel.UpdateFrequency),
+	}
+}
+
+func (it *rdfItem) toItem(w *warnings) model.Item {
+	return model.Item{
+		Title:       it.Title,
+		Description: it.Description,
+		Content:     it.Content,
+		PubDate:     parseDate(it.Date, "dc:date", w),
+		Link:        strings.TrimSpace(it.Link),
+		Guid:        model.Guid{Value: strings.TrimSpace(it.About), IsPermaLink: false},
+		Author:      it.Creator,
+		Categories:  namedCategories(it.Subjects),
+	}
+}
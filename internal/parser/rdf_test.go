@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const slashdotRdf = `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+         xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:slash="http://purl.org/rss/1.0/modules/slash/"
+         xmlns:syn="http://purl.org/rss/1.0/modules/syndication/">
+  <channel rdf:about="https://slashdot.org/">
+    <title>Slashdot</title>
+    <link>https://slashdot.org/</link>
+    <description>News for nerds, stuff that matters</description>
+    <dc:language>en-us</dc:language>
+    <syn:updatePeriod>Hourly</syn:updatePeriod>
+    <syn:updateFrequency>2</syn:updateFrequency>
+    <items>
+      <rdf:Seq>
+        <rdf:li rdf:resource="https://science.slashdot.org/story/25/07/27/1"/>
+        <rdf:li rdf:resource="https://tech.slashdot.org/story/25/07/27/2"/>
+      </rdf:Seq>
+    </items>
+  </channel>
+  <image rdf:about="https://a.fsdn.com/sd/topics/topicslashdot.gif">
+    <title>Slashdot</title>
+    <url>https://a.fsdn.com/sd/topics/topicslashdot.gif</url>
+  </image>
+  <item rdf:about="https://science.slashdot.org/story/25/07/27/1">
+    <title>Item 1</title>
+    <link>https://science.slashdot.org/story/25/07/27/1</link>
+    <description>Item 1 description</description>
+    <dc:creator>msmash</dc:creator>
+    <dc:date>2025-07-27T13:45:00+03:00</dc:date>
+    <slash:comments>42</slash:comments>
+  </item>
+  <item rdf:about="https://tech.slashdot.org/story/25/07/27/2">
+    <title>Item 2</title>
+    <link>https://tech.slashdot.org/story/25/07/27/2</link>
+    <description>Item 2 description</description>
+    <dc:creator>BeauHD</dc:creator>
+    <dc:date>2025-07-27T10:45Z</dc:date>
+  </item>
+</rdf:RDF>`
+
+const journalRdf = `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns="http://purl.org/rss/1.0/" xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+         xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:prism="http://prismstandard.org/namespaces/basic/2.0/">
+  <channel rdf:about="https://www.nature.com/nature.rss">
+    <title>Nature</title>
+    <description>Nature is a weekly international journal</description>
+    <link>https://www.nature.com/nature</link>
+  </channel>
+  <item rdf:about="https://www.nature.com/articles/s41586-025-0001">
+    <title>A new phase of matter</title>
+    <link>https://www.nature.com/articles/s41586-025-0001</link>
+    <dc:creator>Alice Smith</dc:creator>
+    <dc:date>2025-07-27</dc:date>
+    <prism:doi>10.1038/s41586-025-0001</prism:doi>
+  </item>
+</rdf:RDF>`
+
+func TestParse_Rdf(t *testing.T) {
+	t.Run("Slashdot", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(slashdotRdf))
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Slashdot", channel.Title)
+		require.Equal(t, "en-us", channel.Language)
+		require.Equal(t, "News for nerds, stuff that matters", channel.Description)
+		require.Equal(t, "https://slashdot.org/", channel.Link)
+		require.Equal(t, "https://a.fsdn.com/sd/topics/topicslashdot.gif", channel.ImageUrl)
+		require.Equal(t, "hourly", channel.UpdatePeriod)
+		require.Equal(t, 2, channel.UpdateFrequency)
+		require.Len(t, channel.Items, 2)
+
+		item := channel.Items[0]
+		require.Equal(t, "Item 1", item.Title)
+		require.Equal(t, "Item 1 description", item.Description)
+		require.Equal(t, "msmash", item.Author)
+		require.Equal(t, "https://science.slashdot.org/story/25/07/27/1", item.Link)
+		require.Equal(t, "https://science.slashdot.org/story/25/07/27/1", item.Guid.Value)
+		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
+
+		item = channel.Items[1]
+		require.Equal(t, "Item 2", item.Title)
+		require.Equal(t, "BeauHD", item.Author)
+		require.True(t, time.Time(item.PubDate).Equal(expectedDateTime))
+	})
+
+	t.Run("ScientificJournal", func(t *testing.T) {
+		rss, err := Parser{}.Parse([]byte(journalRdf))
+
+		require.NoError(t, err)
+		require.Len(t, rss.Channels, 1)
+
+		channel := rss.Channels[0]
+		require.Equal(t, "Nature", channel.Title)
+		require.Len(t, channel.Items, 1)
+
+		item := channel.Items[0]
+		require.Equal(t, "A new phase of matter", item.Title)
+		require.Equal(t, "Alice Smith", item.Author)
+		require.True(t, time.Time(item.PubDate).Equal(time.Date(2025, 7, 27, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("InvalidXml", func(t *testing.T) {
+		invalidXml := []byte(`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"><channel>`)
+
+		rss, err := Parser{}.Parse(invalidXml)
+
+		require.Error(t, err)
+		require.Empty(t, rss.Channels)
+	})
+}
@@ -0,0 +1,189 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"slices"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// maxEntityNameLength bounds the search for the ';' that ends an entity name.
+const maxEntityNameLength = 32
+
+// parseRepaired is the fallback for XML feeds that are not well-formed. The
+// document is repaired and decoded leniently. If that fails too, the error
+// of the strict decoder is returned, since it points at the actual problem.
+func parseRepaired(bs []byte, parseErr error) (model.Rss, error) {
+	repaired, warnings := repairXml(bs)
+
+	rss, err := parse(repaired, true)
+	if err != nil {
+		return model.Rss{}, parseErr
+	}
+
+	recovered := fmt.Sprintf("recovered from malformed XML: %v", parseErr)
+	rss.Warnings = slices.Concat([]string{recovered}, warnings, rss.Warnings)
+
+	return rss, nil
+}
+
+// repairXml removes the control characters that XML forbids and escapes the
+// ampersands that don't start a reference. CDATA sections and comments are
+// left alone, apart from the control characters. It returns what it repaired.
+func repairXml(bs []byte) ([]byte, []string) {
+	var (
+		out                                     bytes.Buffer
+		removed, escaped, htmlEntities, unknown int
+		inCdata, inComment                      bool
+	)
+
+	out.Grow(len(bs))
+
+	for i := 0; i < len(bs); {
+		rest := bs[i:]
+
+		switch {
+		case isForbiddenControl(bs[i]):
+			removed++
+			i++
+
+			continue
+		case inCdata || inComment:
+			end := "]]>"
+			if inComment {
+				end = "-->"
+			}
+
+			if bytes.HasPrefix(rest, []byte(end)) {
+				inCdata, inComment = false, false
+				out.WriteString(end)
+				i += len(end)
+
+				continue
+			}
+		case bytes.HasPrefix(rest, []byte("<![CDATA[")):
+			inCdata = true
+		case bytes.HasPrefix(rest, []byte("<!--")):
+			inComment = true
+		case bs[i] == '&':
+			n, name := referenceLength(rest)
+			if n == 0 {
+				escaped++
+				out.WriteString("&amp;")
+				i++
+
+				continue
+			}
+
+			if name != "" && !isPredefinedEntity(name) {
+				if _, ok := xml.HTMLEntity[name]; ok {
+					htmlEntities++
+				} else {
+					unknown++
+				}
+			}
+
+			out.Write(rest[:n])
+			i += n
+
+			continue
+		}
+
+		out.WriteByte(bs[i])
+		i++
+	}
+
+	var warnings []string
+
+	if removed > 0 {
+		warnings = append(warnings, fmt.Sprintf("removed %d invalid control characters", removed))
+	}
+
+	if escaped > 0 {
+		warnings = append(warnings, fmt.Sprintf("escaped %d bare ampersands", escaped))
+	}
+
+	if htmlEntities > 0 {
+		warnings = append(warnings, fmt.Sprintf("decoded %d HTML entities", htmlEntities))
+	}
+
+	if unknown > 0 {
+		warnings = append(warnings, fmt.Sprintf("kept %d unknown entities as text", unknown))
+	}
+
+	return out.Bytes(), warnings
+}
+
+// referenceLength returns the length of the character or entity reference
+// at the start of b, and the entity name for entity references. The length
+// is 0 when b doesn't start with a well-formed reference.
+func referenceLength(b []byte) (int, string) {
+	end := bytes.IndexByte(b[:min(len(b), maxEntityNameLength+2)], ';')
+	if end < 2 {
+		return 0, ""
+	}
+
+	ref := b[1:end]
+
+	if ref[0] == '#' {
+		digits, isDigit := ref[1:], isDecimalDigit
+		if len(digits) > 0 && (digits[0] == 'x' || digits[0] == 'X') {
+			digits, isDigit = digits[1:], isHexDigit
+		}
+
+		if len(digits) == 0 || !all(digits, isDigit) {
+			return 0, ""
+		}
+
+		return end + 1, ""
+	}
+
+	if !isNameStart(ref[0]) || !all(ref[1:], isNameChar) {
+		return 0, ""
+	}
+
+	return end + 1, string(ref)
+}
+
+// isForbiddenControl reports whether c is an ASCII control character that
+// XML 1.0 doesn't allow anywhere in a document.
+func isForbiddenControl(c byte) bool {
+	return c < ' ' && c != '\t' && c != '\n' && c != '\r'
+}
+
+func isPredefinedEntity(name string) bool {
+	switch name {
+	case "amp", "lt", "gt", "quot", "apos":
+		return true
+	default:
+		return false
+	}
+}
+
+func all(b []byte, f func(byte) bool) bool {
+	for _, c := range b {
+		if !f(c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDecimalDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDecimalDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func isNameStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == ':'
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || isDecimalDigit(c) || c == '-' || c == '.'
+}
@@ -0,0 +1,326 @@
+package parser
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// sniffLength is how much of a streamed document is looked at to tell JSON
+// feeds from XML ones.
+const sniffLength = 512
+
+// StreamHandler receives the channels and items decoded by Parser.Stream.
+// Any of the functions may be nil. An error returned by a function stops the
+// decoding and is returned by Stream as it is.
+type StreamHandler struct {
+	// Channel is called once for each channel, before any of its items.
+	// The channel has no items.
+	Channel func(channel model.Channel) error
+	// Item is called for each item of the channel passed last to Channel.
+	Item func(item model.Item) error
+	// ChannelUpdate is called at the end of the channel passed last to
+	// Channel if elements that came after its items changed its metadata,
+	// e.g. a <ttl> or an <image> at the end of an RSS channel. It gets the
+	// complete channel, again without items.
+	ChannelUpdate func(channel model.Channel) error
+	// Warning is called with what Parse would report in the Warnings of
+	// its result, before the channel or item it concerns is passed on.
+	Warning func(warning string)
+}
+
+// Stream decodes a feed like Parse, but passes its channels and items to h
+// one at a time instead of returning them all at once, so the memory it needs
+// doesn't grow with the number of items. XML feeds are decoded token by token
+// straight from r. Since a channel is passed on at its first item, channel
+// elements that come after the items are passed on separately, see
+// StreamHandler.ChannelUpdate. Unlike Parse, Stream doesn't repair malformed
+// documents, so callers that need that can fall back to Parse when it fails.
+// JSON feeds are read at once.
+func (Parser) Stream(r io.Reader, h StreamHandler) error {
+	br := bufio.NewReader(r)
+	s := &stream{handler: h}
+
+	// Peek fails for documents shorter than sniffLength, which are looked at
+	// whole then.
+	head, _ := br.Peek(sniffLength) //nolint:errcheck
+	if isJson(head) {
+		return streamJsonFeed(br, s)
+	}
+
+	d := xml.NewDecoder(br)
+	d.CharsetReader = decoder.CharsetReader
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return fmt.Errorf("failed decoding xml data: %w", err)
+		}
+
+		if se, ok := tok.(xml.StartElement); ok {
+			return s.decodeRoot(d, se)
+		}
+	}
+}
+
+// stream passes decoded channels and items to the handler, remembering
+// whether the handler failed.
+type stream struct {
+	handler  StreamHandler
+	warnings warnings
+	err      error
+}
+
+// flushWarnings passes on the warnings collected since the last call.
+func (s *stream) flushWarnings() {
+	if s.handler.Warning != nil {
+		for _, warning := range s.warnings {
+			s.handler.Warning(warning)
+		}
+	}
+
+	s.warnings = nil
+}
+
+func (s *stream) channel(channel model.Channel) error {
+	s.flushWarnings()
+
+	if s.handler.Channel == nil {
+		return nil
+	}
+
+	s.err = s.handler.Channel(channel)
+
+	return s.err
+}
+
+func (s *stream) channelUpdate(channel model.Channel) error {
+	s.flushWarnings()
+
+	if s.handler.ChannelUpdate == nil {
+		return nil
+	}
+
+	s.err = s.handler.ChannelUpdate(channel)
+
+	return s.err
+}
+
+func (s *stream) item(item model.Item) error {
+	s.flushWarnings()
+
+	if s.handler.Item == nil {
+		return nil
+	}
+
+	s.err = s.handler.Item(item)
+
+	return s.err
+}
+
+// decodeRoot decodes the document from its root element, which tells the
+// format the same way as detectFormat does. finish ends the channel once the
+// whole document has been read.
+func (s *stream) decodeRoot(d *xml.Decoder, root xml.StartElement) error {
+	var (
+		v      any
+		finish func() error
+		what   string
+	)
+
+	switch {
+	case root.Name.Local == "feed" && root.Name.Space == atomNamespace:
+		feed := &atomStream{}
+		feed.Entries = itemStream[atomEntry]{
+			stream:   s,
+			metadata: func() any { return feed.atomFeed },
+			channel:  feed.toChannel,
+			item:     func(e *atomEntry) model.Item { return feed.entryItem(e, &s.warnings) },
+		}
+		v, finish, what = feed, feed.Entries.end, "atom"
+	case root.Name.Local == "RDF" && root.Name.Space == rdfNamespace:
+		feed := &rdfStream{}
+		feed.Items = itemStream[rdfItem]{
+			stream:   s,
+			metadata: func() any { return feed.rdfFeed },
+			channel:  feed.toChannel,
+			item:     func(it *rdfItem) model.Item { return it.toItem(&s.warnings) },
+		}
+		v, finish, what = feed, feed.Items.end, "rdf"
+	default:
+		v, finish, what = &rssStream{Channels: rssChannelStream{stream: s}}, func() error { return nil }, "xml"
+	}
+
+	if err := d.DecodeElement(v, &root); err != nil {
+		if s.err != nil {
+			return s.err
+		}
+
+		return fmt.Errorf("failed unmarshalling %s data: %w", what, err)
+	}
+
+	return finish()
+}
+
+// itemStream takes the place of the item slice of a feed. Each item element
+// is decoded as a T, converted and passed on, instead of being collected.
+type itemStream[T any] struct {
+	stream *stream
+	// metadata returns a copy of the channel metadata decoded so far, as it
+	// is in the document, and channel converts it.
+	metadata func() any
+	channel  func(w *warnings) model.Channel
+	item     func(*T) model.Item
+
+	started bool
+	// startMetadata and startWarnings are what the channel was passed on
+	// with.
+	startMetadata any
+	startWarnings warnings
+}
+
+// start passes the channel on, unless it has been already.
+func (is *itemStream[T]) start() error {
+	if is.started {
+		return nil
+	}
+
+	is.started = true
+	is.startMetadata = is.metadata()
+
+	channel := is.channel(&is.startWarnings)
+	is.stream.warnings = append(is.stream.warnings, is.startWarnings...)
+
+	return is.stream.channel(channel)
+}
+
+// end passes the channel on again if elements that came after the items
+// changed it, reporting only the warnings that are new.
+func (is *itemStream[T]) end() error {
+	if err := is.start(); err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(is.startMetadata, is.metadata()) {
+		return nil
+	}
+
+	var w warnings
+
+	channel := is.channel(&w)
+
+	for _, warning := range w {
+		if !slices.Contains(is.startWarnings, warning) {
+			is.stream.warnings = append(is.stream.warnings, warning)
+		}
+	}
+
+	return is.stream.channelUpdate(channel)
+}
+
+func (is *itemStream[T]) UnmarshalXML(d *xml.Decoder, se xml.StartElement) error {
+	if err := is.start(); err != nil {
+		return err
+	}
+
+	var v T
+
+	if err := d.DecodeElement(&v, &se); err != nil {
+		return err
+	}
+
+	return is.stream.item(is.item(&v))
+}
+
+// rssStream is model.Rss with channels that are decoded one by one.
+type rssStream struct {
+	Channels rssChannelStream `xml:"channel"`
+}
+
+type rssChannelStream struct {
+	stream *stream
+}
+
+func (cs *rssChannelStream) UnmarshalXML(d *xml.Decoder, se xml.StartElement) error {
+	channel := &rssStreamChannel{}
+	channel.Items = itemStream[model.Item]{
+		stream:   cs.stream,
+		metadata: func() any { return channel.Channel },
+		channel: func(w *warnings) model.Channel {
+			normalized := channel.Channel
+			normalizeRssChannel(&normalized, w)
+
+			return normalized
+		},
+		item: func(item *model.Item) model.Item {
+			normalizeRssItem(item, &cs.stream.warnings)
+
+			return *item
+		},
+	}
+
+	if err := d.DecodeElement(channel, &se); err != nil {
+		return err
+	}
+
+	return channel.Items.end()
+}
+
+// rssStreamChannel decodes the channel metadata into the embedded Channel,
+// whose item slice is shadowed by the item stream.
+type rssStreamChannel struct {
+	model.Channel
+
+	Items itemStream[model.Item] `xml:"item"`
+}
+
+type atomStream struct {
+	atomFeed
+
+	Entries itemStream[atomEntry] `xml:"entry"`
+}
+
+type rdfStream struct {
+	rdfFeed
+
+	Items itemStream[rdfItem] `xml:"item"`
+}
+
+// streamJsonFeed reads a JSON feed at once and passes it on like the XML
+// feeds are.
+func streamJsonFeed(r io.Reader, s *stream) error {
+	bs, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed reading json data: %w", err)
+	}
+
+	rss, err := parseJsonFeed(bs)
+	if err != nil {
+		return err
+	}
+
+	s.warnings = rss.Warnings
+
+	for _, channel := range rss.Channels {
+		items := channel.Items
+		channel.Items = nil
+
+		if err := s.channel(channel); err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := s.item(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
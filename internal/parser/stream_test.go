@@ -0,0 +1,210 @@
+package parser
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// collect streams the document and assembles what the handler receives the
+// way Parse returns it. It returns how many channel updates it got, too.
+func collect(t *testing.T, bs []byte) (model.Rss, int) {
+	t.Helper()
+
+	var (
+		rss     model.Rss
+		updates int
+	)
+
+	err := Parser{}.Stream(bytes.NewReader(bs), StreamHandler{
+		Channel: func(channel model.Channel) error {
+			require.Nil(t, channel.Items)
+			rss.Channels = append(rss.Channels, channel)
+
+			return nil
+		},
+		Item: func(item model.Item) error {
+			last := &rss.Channels[len(rss.Channels)-1]
+			last.Items = append(last.Items, item)
+
+			return nil
+		},
+		ChannelUpdate: func(channel model.Channel) error {
+			require.Nil(t, channel.Items)
+
+			last := &rss.Channels[len(rss.Channels)-1]
+			channel.Items = last.Items
+			*last = channel
+			updates++
+
+			return nil
+		},
+		Warning: func(warning string) {
+			rss.Warnings = append(rss.Warnings, warning)
+		},
+	})
+	require.NoError(t, err)
+
+	return rss, updates
+}
+
+func TestStream(t *testing.T) {
+	t.Run("MatchesParse", func(t *testing.T) {
+		windows1251, err := os.ReadFile("testdata/windows-1251.xml")
+		require.NoError(t, err)
+
+		documents := map[string][]byte{
+			"Rss": []byte(`
+				<rss xmlns:media="http://search.yahoo.com/mrss/">
+					<channel>
+						<title>Channel 1</title>
+						<link>https://example.com/</link>
+						<category>Go</category>
+						<item>
+							<title>Item 1</title>
+							<guid>https://example.com/1</guid>
+							<category>Go</category>
+							<media:content url="https://example.com/1.mp4" type="video/mp4"/>
+						</item>
+						<item><title>Item 2</title><pubDate>soon</pubDate></item>
+						<ttl>120</ttl>
+						<skipDays><day>Sunday</day></skipDays>
+						<image><url>https://example.com/logo.png</url></image>
+					</channel>
+					<channel>
+						<title>Channel 2</title>
+						<ttl>soon</ttl>
+					</channel>
+				</rss>`),
+			"Atom": []byte(`
+				<feed xmlns="http://www.w3.org/2005/Atom">
+					<title>Feed</title>
+					<author><name>Jane</name></author>
+					<entry><id>1</id><title>Entry 1</title><summary>Summary</summary></entry>
+					<entry><id>2</id><title>Entry 2</title><updated>later</updated><author><name>John</name></author></entry>
+					<logo>https://example.com/logo.png</logo>
+				</feed>`),
+			"Rdf": []byte(`
+				<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+					<channel><title>Channel</title><link>https://example.com/</link></channel>
+					<item rdf:about="https://example.com/1"><title>Item 1</title></item>
+					<image><url>https://example.com/logo.png</url></image>
+				</rdf:RDF>`),
+			"Json": []byte(`{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "Feed",
+				"items": [{"id": "1", "title": "Item 1", "content_text": "Text", "date_published": "today"}]
+			}`),
+			"Charset": windows1251,
+		}
+
+		for name, bs := range documents {
+			t.Run(name, func(t *testing.T) {
+				expected, err := Parser{}.Parse(bs)
+				require.NoError(t, err)
+
+				actual, _ := collect(t, bs)
+
+				require.Equal(t, expected, actual)
+			})
+		}
+	})
+
+	t.Run("ChannelUpdate", func(t *testing.T) {
+		_, updates := collect(t, []byte(`<rss>
+				<channel><title>Channel 1</title><item><title>Item 1</title></item></channel>
+				<channel><title>Channel 2</title><item><title>Item 2</title></item><ttl>bad</ttl></channel>
+			</rss>`))
+
+		require.Equal(t, 1, updates)
+	})
+
+	t.Run("ChannelWithoutItems", func(t *testing.T) {
+		rss, _ := collect(t, []byte(`<feed xmlns="http://www.w3.org/2005/Atom"><title>Empty</title></feed>`))
+
+		require.Len(t, rss.Channels, 1)
+		require.Equal(t, "Empty", rss.Channels[0].Title)
+		require.Empty(t, rss.Channels[0].Items)
+	})
+
+	t.Run("HandlerError", func(t *testing.T) {
+		errStop := errors.New("stop")
+		items := 0
+
+		err := Parser{}.Stream(strings.NewReader(`<rss><channel><item/><item/></channel></rss>`), StreamHandler{
+			Item: func(model.Item) error {
+				items++
+
+				return errStop
+			},
+		})
+
+		require.ErrorIs(t, err, errStop)
+		require.Equal(t, 1, items)
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		err := Parser{}.Stream(strings.NewReader(`<rss><channel><title>A & B</title></channel></rss>`), StreamHandler{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		require.Error(t, Parser{}.Stream(strings.NewReader(""), StreamHandler{}))
+	})
+}
+
+// largeFeed returns an RSS document with n items.
+func largeFeed(n int) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(`<rss><channel><title>Large</title><link>https://example.com/</link>`)
+
+	for i := range n {
+		fmt.Fprintf(&b, `<item><title>Item %d</title><link>https://example.com/%d</link>`+
+			`<guid>https://example.com/%d</guid><pubDate>Sat, 27 Jul 2025 13:45:00 +0300</pubDate>`+
+			`<description>&lt;p&gt;Description of item %d&lt;/p&gt;</description></item>`, i, i, i, i)
+	}
+
+	b.WriteString(`</channel></rss>`)
+
+	return b.Bytes()
+}
+
+const benchmarkItems = 10000
+
+func BenchmarkParse(b *testing.B) {
+	bs := largeFeed(benchmarkItems)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bs)))
+	b.ResetTimer()
+
+	for range b.N {
+		if _, err := (Parser{}).Parse(bs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStream(b *testing.B) {
+	bs := largeFeed(benchmarkItems)
+	handler := StreamHandler{Item: func(model.Item) error { return nil }}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(bs)))
+	b.ResetTimer()
+
+	for range b.N {
+		if err := (Parser{}).Stream(bytes.NewReader(bs), handler); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/marchuknikolay/rss-parser/internal/storage"
+)
+
+// batchQueue queues the statements of the helpers that take a
+// storage.CommandExecutor in a batch instead of executing them. Their
+// failures are only reported once the batch is sent.
+type batchQueue struct {
+	batch *pgx.Batch
+}
+
+func (q batchQueue) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	q.batch.Queue(sql, args...)
+
+	return pgconn.CommandTag{}, nil
+}
+
+// sendBatch sends b and passes its results to read, which has to read one
+// result per queued statement. Empty batches aren't sent.
+func sendBatch(
+	ctx context.Context,
+	sender storage.BatchSender,
+	b *pgx.Batch,
+	read func(results pgx.BatchResults) error,
+) error {
+	if b.Len() == 0 {
+		return nil
+	}
+
+	results := sender.SendBatch(ctx, b)
+
+	err := read(results)
+	if closeErr := results.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
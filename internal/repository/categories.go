@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/storage"
+)
+
+// storeCategories creates the categories that aren't stored yet and returns
+// the ids of all of them. The stored rows aren't updated and the new ones are
+// inserted in one statement, in sorted order, so that imports sharing
+// categories don't lock them in different orders and deadlock.
+func storeCategories(
+	ctx context.Context,
+	st storage.Interface,
+	categories []model.Category,
+) (map[model.Category]int, error) {
+	ids := make(map[model.Category]int, len(categories))
+
+	unique := slices.Clone(categories)
+	slices.SortFunc(unique, func(a, b model.Category) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), strings.Compare(a.Domain, b.Domain))
+	})
+	unique = slices.Compact(unique)
+
+	if len(unique) == 0 {
+		return ids, nil
+	}
+
+	names := make([]string, len(unique))
+	domains := make([]string, len(unique))
+
+	for i, c := range unique {
+		names[i] = c.Name
+		domains[i] = c.Domain
+	}
+
+	query := `
+		INSERT INTO categories (name, domain)
+		SELECT * FROM unnest($1::text[], $2::text[])
+		ON CONFLICT (name, domain) DO NOTHING
+	`
+
+	if _, err := st.ExecExecutor().Exec(ctx, query, names, domains); err != nil {
+		return nil, fmt.Errorf("failed to save categories: %w", err)
+	}
+
+	query = `
+		SELECT id, name, domain FROM categories
+		WHERE (name, domain) IN (SELECT * FROM unnest($1::text[], $2::text[]))
+	`
+
+	rows, err := st.QueryExecutor().Query(ctx, query, names, domains)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			id int
+			c  model.Category
+		)
+
+		if err := rows.Scan(&id, &c.Name, &c.Domain); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		ids[c] = id
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
+
+	for _, c := range unique {
+		if _, ok := ids[c]; !ok {
+			return nil, fmt.Errorf("category %q wasn't saved", c.Name)
+		}
+	}
+
+	return ids, nil
+}
+
+// linkCategories links the row ownerId to its categories through joinTable.
+// ids are the ids of the categories, as returned by storeCategories.
+func linkCategories(
+	ctx context.Context,
+	executor storage.CommandExecutor,
+	joinTable, key string,
+	ownerId int,
+	categories []model.Category,
+	ids map[model.Category]int,
+) error {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	categoryIds := make([]int, len(categories))
+
+	for i, c := range categories {
+		categoryIds[i] = ids[c]
+	}
+
+	query := `
+		INSERT INTO ` + joinTable + ` (` + key + `, category_id)
+		SELECT $1, unnest($2::int[])
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := executor.Exec(ctx, query, ownerId, categoryIds); err != nil {
+		return fmt.Errorf("failed to link categories: %w", err)
+	}
+
+	return nil
+}
+
+// saveCategories links the row ownerId to its categories through joinTable,
+// creating the categories that aren't stored yet.
+func saveCategories(
+	ctx context.Context,
+	st storage.Interface,
+	joinTable, key string,
+	ownerId int,
+	categories []model.Category,
+) error {
+	ids, err := storeCategories(ctx, st, categories)
+	if err != nil {
+		return err
+	}
+
+	return linkCategories(ctx, st.ExecExecutor(), joinTable, key, ownerId, categories, ids)
+}
+
+// replaceCategories drops the categories of the row ownerId and stores the
+// given ones instead.
+func replaceCategories(
+	ctx context.Context,
+	st storage.Interface,
+	joinTable, key string,
+	ownerId int,
+	categories []model.Category,
+) error {
+	_, err := st.ExecExecutor().Exec(ctx, `DELETE FROM `+joinTable+` WHERE `+key+` = $1`, ownerId)
+	if err != nil {
+		return fmt.Errorf("failed to delete categories: %w", err)
+	}
+
+	return saveCategories(ctx, st, joinTable, key, ownerId, categories)
+}
+
+// decodeCategories reads the categories column selected by itemColumns and
+// channelColumns. Rows without categories look the same as freshly parsed
+// ones.
+func decodeCategories(bs []byte) ([]model.Category, error) {
+	var categories []model.Category
+
+	if err := json.Unmarshal(bs, &categories); err != nil {
+		return nil, fmt.Errorf("failed to decode categories: %w", err)
+	}
+
+	if len(categories) == 0 {
+		return nil, nil
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/repository/mock"
+)
+
+func TestStoreCategories(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var inserted []any
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: &mock.MockCommandExecutor{
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+					inserted = args
+
+					return pgconn.NewCommandTag("INSERT 0 2"), nil
+				},
+			},
+			QueryExecutorFunc: mockCategoryQueryer(),
+		}
+
+		go1 := model.Category{Name: "go", Domain: "b"}
+		go2 := model.Category{Name: "go", Domain: "a"}
+		rust := model.Category{Name: "rust"}
+
+		ids, err := storeCategories(context.Background(), mockStorage, []model.Category{rust, go1, go2, rust})
+
+		require.NoError(t, err)
+		require.Equal(t, []any{[]string{"go", "go", "rust"}, []string{"a", "b", ""}}, inserted)
+		require.Equal(t, map[model.Category]int{go2: 1, go1: 2, rust: 3}, ids)
+	})
+
+	t.Run("NoCategories", func(t *testing.T) {
+		ids, err := storeCategories(context.Background(), &mock.MockStorage{}, nil)
+
+		require.NoError(t, err)
+		require.Empty(t, ids)
+	})
+
+	t.Run("FailExec", func(t *testing.T) {
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: &mock.MockCommandExecutor{
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+					return pgconn.NewCommandTag(""), errors.New("Executing failed")
+				},
+			},
+		}
+
+		ids, err := storeCategories(context.Background(), mockStorage, []model.Category{{Name: "go"}})
+
+		require.Error(t, err)
+		require.Nil(t, ids)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: &mock.MockCommandExecutor{
+				ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+					return pgconn.NewCommandTag("INSERT 0 0"), nil
+				},
+			},
+			QueryExecutorFunc: &mock.MockRowQueryer{
+				QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+					return &mock.MockRows{}, nil
+				},
+			},
+		}
+
+		ids, err := storeCategories(context.Background(), mockStorage, []model.Category{{Name: "go"}})
+
+		require.Error(t, err)
+		require.Nil(t, ids)
+	})
+}
+
+// mockCategoryQueryer answers the lookup of storeCategories, numbering the
+// categories it is passed from 1.
+func mockCategoryQueryer() *mock.MockRowQueryer {
+	return &mock.MockRowQueryer{
+		QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+			names, domains := args[0].([]string), args[1].([]string) //nolint:errcheck
+			i := 0
+
+			return &mock.MockRows{
+				NextFunc: func() bool { return i < len(names) },
+				ScanFunc: func(dest ...any) error {
+					*(dest[0].(*int)) = i + 1         //nolint:errcheck
+					*(dest[1].(*string)) = names[i]   //nolint:errcheck
+					*(dest[2].(*string)) = domains[i] //nolint:errcheck
+					i++
+
+					return nil
+				},
+			}, nil
+		},
+	}
+}
@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -13,33 +15,130 @@ import (
 
 var ErrChannelNotFound = errors.New("channel not found")
 
-type ChannelRepositoryInterface interface {
-	Save(ctx context.Context, channel *model.Channel) (int, error)
+// channelColumns selects the categories of a channel as a JSON array, so that
+// channels are read with a single query.
+const channelColumns = `id, title, language, description, source_url, link, image_url, last_build_date, ttl,
+	generator, skip_hours, skip_days, update_period, update_frequency, etag, last_modified, next_fetch_at,
+	last_fetched_at, last_error_at, last_error, folder,
+	COALESCE((
+		SELECT json_agg(json_build_object('Name', c.name, 'Domain', c.domain) ORDER BY c.name, c.domain)
+		FROM categories c
+		JOIN channel_categories cc ON cc.category_id = c.id
+		WHERE cc.channel_id = channels.id
+	), '[]')`
+
+// ChannelReader looks up stored channels.
+type ChannelReader interface {
 	GetAll(ctx context.Context) ([]model.Channel, error)
 	GetById(ctx context.Context, id int) (model.Channel, error)
-	Delete(ctx context.Context, id int) error
+}
+
+// ChannelUpserter stores the channels of an imported feed, replacing the ones
+// previously imported from the same URL.
+type ChannelUpserter interface {
+	LockSourceUrl(ctx context.Context, sourceUrl string) error
+	GetBySourceUrl(ctx context.Context, sourceUrl string) ([]model.Channel, error)
+	Save(ctx context.Context, channel *model.Channel) (int, error)
+	Replace(ctx context.Context, id int, channel *model.Channel) error
+}
+
+// ChannelEditor applies the changes a user makes to a channel.
+type ChannelEditor interface {
 	Update(ctx context.Context, id int, title, language, description string) (model.Channel, error)
+	Delete(ctx context.Context, id int) error
+}
+
+// ChannelFetchRecorder keeps the fetch state of channels, which tells the
+// scheduler when to refresh them.
+type ChannelFetchRecorder interface {
+	MarkFetched(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error
+	MarkFailed(ctx context.Context, sourceUrl string, failedAt, nextFetchAt time.Time, reason string) error
+}
+
+type ChannelRepositoryInterface interface {
+	ChannelReader
+	ChannelUpserter
+	ChannelEditor
+	ChannelFetchRecorder
 }
 
 type ChannelRepository struct {
 	storage.Interface
 }
 
+// Save stores a channel together with its categories. It takes several
+// statements, so it is meant to be called in a transaction.
 func (r *ChannelRepository) Save(ctx context.Context, channel *model.Channel) (int, error) {
 	var channelId int
-	query := "INSERT INTO channels (title, language, description) VALUES ($1, $2, $3) RETURNING id"
+	query := `
+		INSERT INTO channels (title, language, description, source_url, link, image_url, last_build_date, ttl,
+			generator, skip_hours, skip_days, update_period, update_frequency, etag, last_modified, folder)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+		RETURNING id
+	`
 
 	executor := r.QueryExecutor()
-	err := executor.QueryRow(ctx, query, channel.Title, channel.Language, channel.Description).Scan(&channelId)
+	err := executor.QueryRow(
+		ctx,
+		query,
+		channel.Title,
+		channel.Language,
+		channel.Description,
+		channel.SourceUrl,
+		channel.Link,
+		channel.ImageUrl,
+		nullTime(channel.LastBuildDate),
+		channel.Ttl,
+		channel.Generator,
+		channel.SkipHours,
+		channel.SkipDays,
+		channel.UpdatePeriod,
+		channel.UpdateFrequency,
+		channel.Etag,
+		channel.LastModified,
+		channel.Folder,
+	).Scan(&channelId)
+	if err != nil {
+		return 0, err
+	}
+
+	err = saveCategories(ctx, r, "channel_categories", "channel_id", channelId, channel.Categories)
+	if err != nil {
+		return 0, err
+	}
 
-	return channelId, err
+	return channelId, nil
 }
 
 func (r *ChannelRepository) GetAll(ctx context.Context) ([]model.Channel, error) {
-	query := `SELECT id, title, language, description FROM channels`
+	query := `SELECT ` + channelColumns + ` FROM channels`
+
+	return r.getChannels(ctx, query)
+}
+
+// GetBySourceUrl returns the channels imported from sourceUrl in the order
+// they were created.
+func (r *ChannelRepository) GetBySourceUrl(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+	query := `SELECT ` + channelColumns + ` FROM channels WHERE source_url = $1 ORDER BY id`
+
+	return r.getChannels(ctx, query, sourceUrl)
+}
+
+// LockSourceUrl makes the transactions that lock sourceUrl after the current
+// one wait until it ends, so that concurrent imports of the same URL don't
+// both create its channels. It has to be called in a transaction.
+func (r *ChannelRepository) LockSourceUrl(ctx context.Context, sourceUrl string) error {
+	executor := r.ExecExecutor()
+	if _, err := executor.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, sourceUrl); err != nil {
+		return fmt.Errorf("failed to lock source url %q: %w", sourceUrl, err)
+	}
 
+	return nil
+}
+
+func (r *ChannelRepository) getChannels(ctx context.Context, query string, args ...any) ([]model.Channel, error) {
 	executor := r.QueryExecutor()
-	rows, err := executor.Query(ctx, query)
+	rows, err := executor.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query channels: %w", err)
 	}
@@ -48,9 +147,8 @@ func (r *ChannelRepository) GetAll(ctx context.Context) ([]model.Channel, error)
 	var channels []model.Channel
 
 	for rows.Next() {
-		var channel model.Channel
-
-		if err := rows.Scan(&channel.Id, &channel.Title, &channel.Language, &channel.Description); err != nil {
+		channel, err := scanChannel(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan channel row: %w", err)
 		}
 
@@ -65,13 +163,13 @@ func (r *ChannelRepository) GetAll(ctx context.Context) ([]model.Channel, error)
 }
 
 func (r *ChannelRepository) GetById(ctx context.Context, id int) (model.Channel, error) {
-	query := `SELECT id, title, language, description FROM channels WHERE id = $1`
+	query := `SELECT ` + channelColumns + ` FROM channels WHERE id = $1`
 
 	executor := r.QueryExecutor()
 	row := executor.QueryRow(ctx, query, id)
 
-	var channel model.Channel
-	if err := row.Scan(&channel.Id, &channel.Title, &channel.Language, &channel.Description); err != nil {
+	channel, err := scanChannel(row)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Channel{}, ErrChannelNotFound
 		}
@@ -107,14 +205,13 @@ func (r *ChannelRepository) Update(
 		UPDATE channels
 		SET title = $1, language = $2, description = $3
 		WHERE id = $4
-		RETURNING id, title, language, description
-	`
+		RETURNING ` + channelColumns
 
 	executor := r.QueryExecutor()
 	row := executor.QueryRow(ctx, query, title, language, description, id)
 
-	var channel model.Channel
-	if err := row.Scan(&channel.Id, &channel.Title, &channel.Language, &channel.Description); err != nil {
+	channel, err := scanChannel(row)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Channel{}, ErrChannelNotFound
 		}
@@ -124,3 +221,135 @@ func (r *ChannelRepository) Update(
 
 	return channel, nil
 }
+
+// Replace overwrites the feed-provided fields and the categories of a channel
+// on re-import. The folder is only changed when the channel being imported has
+// one, so a background refresh keeps the folder chosen at subscription time.
+func (r *ChannelRepository) Replace(ctx context.Context, id int, channel *model.Channel) error {
+	query := `
+		UPDATE channels
+		SET title = $1, language = $2, description = $3, link = $4, image_url = $5, last_build_date = $6,
+			ttl = $7, generator = $8, skip_hours = $9, skip_days = $10, update_period = $11, update_frequency = $12,
+			etag = $13, last_modified = $14, folder = COALESCE(NULLIF($15, ''), folder)
+		WHERE id = $16
+	`
+
+	executor := r.ExecExecutor()
+	tag, err := executor.Exec(
+		ctx,
+		query,
+		channel.Title,
+		channel.Language,
+		channel.Description,
+		channel.Link,
+		channel.ImageUrl,
+		nullTime(channel.LastBuildDate),
+		channel.Ttl,
+		channel.Generator,
+		channel.SkipHours,
+		channel.SkipDays,
+		channel.UpdatePeriod,
+		channel.UpdateFrequency,
+		channel.Etag,
+		channel.LastModified,
+		channel.Folder,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to replace channel with id=%d: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrChannelNotFound
+	}
+
+	return replaceCategories(ctx, r, "channel_categories", "channel_id", id, channel.Categories)
+}
+
+// MarkFetched records a successful refresh of the channels imported from
+// sourceUrl, clears the last error and schedules the next refresh.
+func (r *ChannelRepository) MarkFetched(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error {
+	query := `UPDATE channels SET last_fetched_at = $1, next_fetch_at = $2, last_error = '' WHERE source_url = $3`
+
+	return r.markSourceUrl(ctx, query, fetchedAt, nextFetchAt, sourceUrl)
+}
+
+// MarkFailed records a failed refresh of the channels imported from sourceUrl
+// and schedules the next attempt.
+func (r *ChannelRepository) MarkFailed(
+	ctx context.Context,
+	sourceUrl string,
+	failedAt, nextFetchAt time.Time,
+	reason string,
+) error {
+	query := `UPDATE channels SET last_error_at = $1, next_fetch_at = $2, last_error = $3 WHERE source_url = $4`
+
+	return r.markSourceUrl(ctx, query, failedAt, nextFetchAt, reason, sourceUrl)
+}
+
+func (r *ChannelRepository) markSourceUrl(ctx context.Context, query string, args ...any) error {
+	executor := r.ExecExecutor()
+	tag, err := executor.Exec(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update fetch status of channels: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrChannelNotFound
+	}
+
+	return nil
+}
+
+// scanChannel reads a row selected with channelColumns.
+func scanChannel(row pgx.Row) (model.Channel, error) {
+	var (
+		channel       model.Channel
+		lastBuildDate sql.NullTime
+		nextFetchAt   sql.NullTime
+		lastFetchedAt sql.NullTime
+		lastErrorAt   sql.NullTime
+		categories    []byte
+	)
+
+	if err := row.Scan(
+		&channel.Id,
+		&channel.Title,
+		&channel.Language,
+		&channel.Description,
+		&channel.SourceUrl,
+		&channel.Link,
+		&channel.ImageUrl,
+		&lastBuildDate,
+		&channel.Ttl,
+		&channel.Generator,
+		&channel.SkipHours,
+		&channel.SkipDays,
+		&channel.UpdatePeriod,
+		&channel.UpdateFrequency,
+		&channel.Etag,
+		&channel.LastModified,
+		&nextFetchAt,
+		&lastFetchedAt,
+		&lastErrorAt,
+		&channel.LastError,
+		&channel.Folder,
+		&categories,
+	); err != nil {
+		return model.Channel{}, err
+	}
+
+	channel.LastBuildDate = model.DateTime(lastBuildDate.Time)
+	channel.NextFetchAt = model.DateTime(nextFetchAt.Time)
+	channel.LastFetchedAt = model.DateTime(lastFetchedAt.Time)
+	channel.LastErrorAt = model.DateTime(lastErrorAt.Time)
+
+	decoded, err := decodeCategories(categories)
+	if err != nil {
+		return model.Channel{}, err
+	}
+
+	channel.Categories = decoded
+
+	return channel, nil
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,6 +18,8 @@ import (
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
+const sourceUrl = "https://example.com/feed.xml"
+
 func TestChannelRepository_Save(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expected := 1
@@ -195,6 +200,66 @@ func TestChannelRepository_GetById(t *testing.T) {
 	})
 }
 
+func TestChannelRepository_GetBySourceUrl(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.Channel{testutils.CreateChannelWithId(1)}
+
+		var queriedUrl any
+
+		i := 0
+		mockRows := &mock.MockRows{
+			NextFunc: func() bool { return i < len(expected) },
+			ScanFunc: func(dest ...any) error {
+				channel := expected[i]
+				i++
+
+				fillDestWithChannel(dest, &channel)
+
+				return nil
+			},
+		}
+
+		mockRowQueryer := &mock.MockRowQueryer{
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+				queriedUrl = args[0]
+
+				return mockRows, nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: mockRowQueryer,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		actual, err := repo.GetBySourceUrl(context.Background(), expected[0].SourceUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+		require.Equal(t, expected[0].SourceUrl, queriedUrl)
+	})
+
+	t.Run("FailQuery", func(t *testing.T) {
+		mockRowQueryer := &mock.MockRowQueryer{
+			QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+				return nil, errors.New("Querying failed")
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: mockRowQueryer,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		actual, err := repo.GetBySourceUrl(context.Background(), sourceUrl)
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+}
+
 func TestChannelRepository_Delete(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		id := 1
@@ -314,6 +379,201 @@ func TestChannelRepository_Update(t *testing.T) {
 	})
 }
 
+func TestChannelRepository_Replace(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag("UPDATE 1"), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: mockCategoryQueryer(),
+			ExecExecutorFunc:  mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+		channel := testutils.CreateChannelWithId(1)
+
+		err := repo.Replace(context.Background(), channel.Id, &channel)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("FailExec", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), errors.New("Executing failed")
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: mockCategoryQueryer(),
+			ExecExecutorFunc:  mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+		channel := testutils.CreateChannelWithId(1)
+
+		err := repo.Replace(context.Background(), channel.Id, &channel)
+
+		require.Error(t, err)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: mockCategoryQueryer(),
+			ExecExecutorFunc:  mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+		channel := testutils.CreateChannelWithId(1)
+
+		err := repo.Replace(context.Background(), channel.Id, &channel)
+
+		require.Equal(t, ErrChannelNotFound, err)
+	})
+}
+
+func TestChannelRepository_LockSourceUrl(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var args []any
+
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, a ...any) (pgconn.CommandTag, error) {
+				args = a
+
+				return pgconn.NewCommandTag("SELECT 1"), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		err := repo.LockSourceUrl(context.Background(), sourceUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, []any{sourceUrl}, args)
+	})
+
+	t.Run("FailExec", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), errors.New("Executing failed")
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		err := repo.LockSourceUrl(context.Background(), sourceUrl)
+
+		require.Error(t, err)
+	})
+}
+
+func TestChannelRepository_MarkFetched(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var args []any
+
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, a ...any) (pgconn.CommandTag, error) {
+				args = a
+
+				return pgconn.NewCommandTag("UPDATE 1"), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+		fetchedAt := time.Date(2025, 7, 27, 13, 45, 0, 0, time.UTC)
+		nextFetchAt := fetchedAt.Add(time.Hour)
+
+		err := repo.MarkFetched(context.Background(), sourceUrl, fetchedAt, nextFetchAt)
+
+		require.NoError(t, err)
+		require.Equal(t, []any{fetchedAt, nextFetchAt, sourceUrl}, args)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		err := repo.MarkFetched(context.Background(), sourceUrl, time.Now(), time.Now())
+
+		require.Equal(t, ErrChannelNotFound, err)
+	})
+}
+
+func TestChannelRepository_MarkFailed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		var args []any
+
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, a ...any) (pgconn.CommandTag, error) {
+				args = a
+
+				return pgconn.NewCommandTag("UPDATE 1"), nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+		failedAt := time.Date(2025, 7, 27, 13, 45, 0, 0, time.UTC)
+		nextFetchAt := failedAt.Add(time.Hour)
+
+		err := repo.MarkFailed(context.Background(), sourceUrl, failedAt, nextFetchAt, "timeout")
+
+		require.NoError(t, err)
+		require.Equal(t, []any{failedAt, nextFetchAt, "timeout", sourceUrl}, args)
+	})
+
+	t.Run("FailExec", func(t *testing.T) {
+		mockCommandExecutor := &mock.MockCommandExecutor{
+			ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), errors.New("Executing failed")
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			ExecExecutorFunc: mockCommandExecutor,
+		}
+
+		repo := ChannelRepositoryFactory{}.New(mockStorage)
+
+		err := repo.MarkFailed(context.Background(), sourceUrl, time.Now(), time.Now(), "timeout")
+
+		require.Error(t, err)
+	})
+}
+
 func setupChannelRepository(scanFunc func(dest ...any) error) ChannelRepositoryInterface {
 	mockRow := &mock.MockRow{
 		ScanFunc: scanFunc,
@@ -323,18 +583,58 @@ func setupChannelRepository(scanFunc func(dest ...any) error) ChannelRepositoryI
 		QueryRowFunc: func(ctx context.Context, sql string, args ...any) pgx.Row {
 			return mockRow
 		},
+		QueryFunc: mockCategoryQueryer().QueryFunc,
+	}
+
+	mockCommandExecutor := &mock.MockCommandExecutor{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
 	}
 
 	mockStorage := &mock.MockStorage{
 		QueryExecutorFunc: mockRowQueryer,
+		ExecExecutorFunc:  mockCommandExecutor,
 	}
 
 	return ChannelRepositoryFactory{}.New(mockStorage)
 }
 
 func fillDestWithChannel(dest []any, ch *model.Channel) {
-	*(dest[0].(*int)) = ch.Id             //nolint:errcheck
-	*(dest[1].(*string)) = ch.Title       //nolint:errcheck
-	*(dest[2].(*string)) = ch.Language    //nolint:errcheck
-	*(dest[3].(*string)) = ch.Description //nolint:errcheck
+	*(dest[0].(*int)) = ch.Id                  //nolint:errcheck
+	*(dest[1].(*string)) = ch.Title            //nolint:errcheck
+	*(dest[2].(*string)) = ch.Language         //nolint:errcheck
+	*(dest[3].(*string)) = ch.Description      //nolint:errcheck
+	*(dest[4].(*string)) = ch.SourceUrl        //nolint:errcheck
+	*(dest[5].(*string)) = ch.Link             //nolint:errcheck
+	*(dest[6].(*string)) = ch.ImageUrl         //nolint:errcheck
+	*(dest[7].(*sql.NullTime)) = sql.NullTime{ //nolint:errcheck
+		Time:  time.Time(ch.LastBuildDate),
+		Valid: !ch.LastBuildDate.IsZero(),
+	}
+	*(dest[8].(*int)) = ch.Ttl                  //nolint:errcheck
+	*(dest[9].(*string)) = ch.Generator         //nolint:errcheck
+	*(dest[10].(*[]int)) = ch.SkipHours         //nolint:errcheck
+	*(dest[11].(*[]string)) = ch.SkipDays       //nolint:errcheck
+	*(dest[12].(*string)) = ch.UpdatePeriod     //nolint:errcheck
+	*(dest[13].(*int)) = ch.UpdateFrequency     //nolint:errcheck
+	*(dest[14].(*string)) = ch.Etag             //nolint:errcheck
+	*(dest[15].(*string)) = ch.LastModified     //nolint:errcheck
+	*(dest[16].(*sql.NullTime)) = sql.NullTime{ //nolint:errcheck
+		Time:  time.Time(ch.NextFetchAt),
+		Valid: !ch.NextFetchAt.IsZero(),
+	}
+	*(dest[17].(*sql.NullTime)) = sql.NullTime{ //nolint:errcheck
+		Time:  time.Time(ch.LastFetchedAt),
+		Valid: !ch.LastFetchedAt.IsZero(),
+	}
+	*(dest[18].(*sql.NullTime)) = sql.NullTime{ //nolint:errcheck
+		Time:  time.Time(ch.LastErrorAt),
+		Valid: !ch.LastErrorAt.IsZero(),
+	}
+	*(dest[19].(*string)) = ch.LastError //nolint:errcheck
+	*(dest[20].(*string)) = ch.Folder    //nolint:errcheck
+
+	categories, _ := json.Marshal(ch.Categories) //nolint:errchkjson
+	*(dest[21].(*[]byte)) = categories           //nolint:errcheck
 }
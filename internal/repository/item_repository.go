@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -14,52 +16,256 @@ import (
 
 var ErrItemNotFound = errors.New("item not found")
 
-type ItemRepositoryInterface interface {
-	Save(ctx context.Context, item model.Item, channelId int) error
+// itemColumns selects the enclosures, attachments and categories of an item
+// as JSON arrays, so that items are read with a single query.
+const itemColumns = `id, title, description, pub_date, link, guid, guid_is_permalink, author, duration, episode,
+	season, image_url, explicit, thumbnail_url, content,
+	COALESCE((
+		SELECT json_agg(json_build_object('Url', e.url, 'Type', e.type, 'Length', e.length) ORDER BY e.id)
+		FROM enclosures e
+		WHERE e.item_id = items.id
+	), '[]'),
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'Url', a.url, 'Type', a.type, 'Medium', a.medium, 'Width', a.width, 'Height', a.height,
+			'FileSize', a.file_size, 'Duration', a.duration, 'Description', a.description, 'Credit', a.credit,
+			'ThumbnailUrl', a.thumbnail_url
+		) ORDER BY a.id)
+		FROM attachments a
+		WHERE a.item_id = items.id
+	), '[]'),
+	COALESCE((
+		SELECT json_agg(json_build_object('Name', c.name, 'Domain', c.domain) ORDER BY c.name, c.domain)
+		FROM categories c
+		JOIN item_categories ic ON ic.category_id = c.id
+		WHERE ic.item_id = items.id
+	), '[]')`
+
+// ItemReader looks up stored items.
+type ItemReader interface {
 	GetAll(ctx context.Context) ([]model.Item, error)
 	GetByChannelId(ctx context.Context, channelId int) ([]model.Item, error)
 	GetById(ctx context.Context, itemId int) (model.Item, error)
+	GetByCategory(ctx context.Context, category string) ([]model.Item, error)
+	GetCategories(ctx context.Context) ([]model.CategoryCount, error)
+}
+
+// ItemUpserter stores the items of an imported channel, replacing the ones
+// that changed since the previous import.
+type ItemUpserter interface {
+	GetByKeys(ctx context.Context, channelId int, keys ItemKeys) ([]model.Item, error)
+	SaveAll(ctx context.Context, items []model.Item, channelId int) error
+	Replace(ctx context.Context, id int, item model.Item) error
+}
+
+// ItemKeys are what the items of an imported channel are identified by: the
+// GUID, the link of items without one and the title of items with neither.
+type ItemKeys struct {
+	Guids  []string
+	Links  []string
+	Titles []string
+}
+
+// ItemEditor applies the changes a user makes to an item.
+type ItemEditor interface {
+	Update(
+		ctx context.Context,
+		id int,
+		title, description, link, author string,
+		pubTime time.Time,
+	) (model.Item, error)
 	Delete(ctx context.Context, id int) error
-	Update(ctx context.Context, id int, title, description string, pubTime time.Time) (model.Item, error)
+}
+
+type ItemRepositoryInterface interface {
+	ItemReader
+	ItemUpserter
+	ItemEditor
 }
 
 type ItemRepository struct {
 	storage.Interface
 }
 
-func (r *ItemRepository) Save(ctx context.Context, item model.Item, channelId int) error {
-	query := "INSERT INTO items (title, description, pub_date, channel_id) VALUES ($1, $2, $3, $4)"
+// SaveAll stores new items of a channel together with their enclosures,
+// attachments and categories. The statements are sent in two batches, the
+// items first and then what refers to them, so the number of round trips
+// doesn't grow with the number of items. It is meant to be called in a
+// transaction.
+func (r *ItemRepository) SaveAll(ctx context.Context, items []model.Item, channelId int) error {
+	if len(items) == 0 {
+		return nil
+	}
 
-	executor := r.ExecExecutor()
-	_, err := executor.Exec(ctx, query, item.Title, item.Description, time.Time(item.PubDate), channelId)
+	query := `
+		INSERT INTO items (title, description, pub_date, link, guid, guid_is_permalink, author, duration, episode,
+			season, image_url, explicit, thumbnail_url, content, channel_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+		RETURNING id
+	`
+
+	inserts := &pgx.Batch{}
+
+	for _, item := range items {
+		inserts.Queue(
+			query,
+			item.Title,
+			item.Description,
+			nullTime(item.PubDate),
+			item.Link,
+			item.Guid.Value,
+			item.Guid.IsPermaLink,
+			item.Author,
+			item.Duration,
+			item.Episode,
+			item.Season,
+			item.ImageUrl,
+			item.Explicit,
+			item.ThumbnailUrl,
+			item.Content,
+			channelId,
+		)
+	}
 
-	return err
+	itemIds := make([]int, len(items))
+
+	err := sendBatch(ctx, r.BatchExecutor(), inserts, func(results pgx.BatchResults) error {
+		for i := range itemIds {
+			if err := results.QueryRow().Scan(&itemIds[i]); err != nil {
+				return fmt.Errorf("failed to insert item %q: %w", items[i].Title, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var categories []model.Category
+
+	for _, item := range items {
+		categories = append(categories, item.Categories...)
+	}
+
+	categoryIds, err := storeCategories(ctx, r, categories)
+	if err != nil {
+		return err
+	}
+
+	refs := &pgx.Batch{}
+	queue := batchQueue{batch: refs}
+
+	for i, item := range items {
+		// Queuing doesn't fail, errors are returned by sendBatch.
+		_ = saveMedia(ctx, queue, itemIds[i], item) //nolint:errcheck
+		//nolint:errcheck
+		_ = linkCategories(ctx, queue, "item_categories", "item_id", itemIds[i], item.Categories, categoryIds)
+	}
+
+	return sendBatch(ctx, r.BatchExecutor(), refs, func(results pgx.BatchResults) error {
+		for range refs.Len() {
+			if _, err := results.Exec(); err != nil {
+				return fmt.Errorf("failed to save media and categories of items: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
 func (r *ItemRepository) GetAll(ctx context.Context) ([]model.Item, error) {
-	query := `SELECT id, title, description, pub_date FROM items`
+	query := `SELECT ` + itemColumns + ` FROM items`
 
 	return r.getItems(ctx, query)
 }
 
 func (r *ItemRepository) GetByChannelId(ctx context.Context, channelId int) ([]model.Item, error) {
-	query := `SELECT id, title, description, pub_date FROM items WHERE channel_id = $1`
+	query := `SELECT ` + itemColumns + ` FROM items WHERE channel_id = $1`
 
 	return r.getItems(ctx, query, channelId)
 }
 
-func (r *ItemRepository) GetById(ctx context.Context, itemId int) (model.Item, error) {
-	query := `SELECT id, title, description, pub_date FROM items WHERE id = $1`
+// GetByKeys returns the items of the channel with any of the keys, so that an
+// import only reads the stored items it is about to compare with. Items are
+// selected if any of their keys match, so the caller still has to tell which
+// key identifies them.
+func (r *ItemRepository) GetByKeys(ctx context.Context, channelId int, keys ItemKeys) ([]model.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items WHERE channel_id = $1 AND (guid = ANY($2) OR link = ANY($3)`
+	args := []any{channelId, keys.Guids, keys.Links}
+
+	// Items with neither a GUID nor a link are rare, so their titles have no
+	// index and are only searched for when needed.
+	if len(keys.Titles) > 0 {
+		query += ` OR title = ANY($4)`
+		args = append(args, keys.Titles)
+	}
+
+	return r.getItems(ctx, query+`)`, args...)
+}
+
+// GetByCategory returns the items filed under the category, whatever its
+// domain. Category names are matched case-insensitively.
+func (r *ItemRepository) GetByCategory(ctx context.Context, category string) ([]model.Item, error) {
+	query := `
+		SELECT ` + itemColumns + `
+		FROM items
+		WHERE EXISTS (
+			SELECT 1
+			FROM item_categories ic
+			JOIN categories c ON c.id = ic.category_id
+			WHERE ic.item_id = items.id AND lower(c.name) = lower($1)
+		)
+	`
+
+	return r.getItems(ctx, query, category)
+}
+
+// GetCategories counts the items filed under each category name, ignoring
+// case and domain, in alphabetical order.
+func (r *ItemRepository) GetCategories(ctx context.Context) ([]model.CategoryCount, error) {
+	query := `
+		SELECT min(c.name), count(DISTINCT ic.item_id)
+		FROM categories c
+		JOIN item_categories ic ON ic.category_id = c.id
+		GROUP BY lower(c.name)
+		ORDER BY lower(c.name)
+	`
 
 	executor := r.QueryExecutor()
 
-	var (
-		id                 int
-		title, description string
-		pubDate            time.Time
-	)
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	var categories []model.CategoryCount
+
+	for rows.Next() {
+		var category model.CategoryCount
+
+		if err := rows.Scan(&category.Name, &category.Count); err != nil {
+			return nil, fmt.Errorf("scan failed: %w", err)
+		}
+
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration failed: %w", err)
+	}
 
-	if err := executor.QueryRow(ctx, query, itemId).Scan(&id, &title, &description, &pubDate); err != nil {
+	return categories, nil
+}
+
+func (r *ItemRepository) GetById(ctx context.Context, itemId int) (model.Item, error) {
+	query := `SELECT ` + itemColumns + ` FROM items WHERE id = $1`
+
+	executor := r.QueryExecutor()
+
+	item, err := scanItem(executor.QueryRow(ctx, query, itemId))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Item{}, ErrItemNotFound
 		}
@@ -67,12 +273,7 @@ func (r *ItemRepository) GetById(ctx context.Context, itemId int) (model.Item, e
 		return model.Item{}, fmt.Errorf("failed to scan item: %w", err)
 	}
 
-	return model.Item{
-		Id:          id,
-		Title:       title,
-		Description: description,
-		PubDate:     model.DateTime(pubDate),
-	}, nil
+	return item, nil
 }
 
 func (r *ItemRepository) Delete(ctx context.Context, id int) error {
@@ -94,21 +295,20 @@ func (r *ItemRepository) Delete(ctx context.Context, id int) error {
 func (r *ItemRepository) Update(
 	ctx context.Context,
 	id int,
-	title, description string,
+	title, description, link, author string,
 	pubTime time.Time,
 ) (model.Item, error) {
 	query := `
 		UPDATE items
-		SET title = $1, description = $2, pub_date = $3
-		WHERE id = $4
-		RETURNING id, title, description, pub_date
-	`
+		SET title = $1, description = $2, link = $3, author = $4, pub_date = $5
+		WHERE id = $6
+		RETURNING ` + itemColumns
 
 	executor := r.QueryExecutor()
-	row := executor.QueryRow(ctx, query, title, description, pubTime, id)
+	row := executor.QueryRow(ctx, query, title, description, link, author, pubTime, id)
 
-	var item model.Item
-	if err := row.Scan(&item.Id, &item.Title, &item.Description, &item.PubDate); err != nil {
+	item, err := scanItem(row)
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return model.Item{}, ErrItemNotFound
 		}
@@ -119,6 +319,98 @@ func (r *ItemRepository) Update(
 	return item, nil
 }
 
+// Replace overwrites the feed-provided fields, the enclosures, the attachments
+// and the categories of an item on re-import. Like SaveAll, it is meant to be
+// called in a transaction.
+func (r *ItemRepository) Replace(ctx context.Context, id int, item model.Item) error {
+	query := `
+		UPDATE items
+		SET title = $1, description = $2, pub_date = $3, link = $4, guid = $5, guid_is_permalink = $6, author = $7,
+			duration = $8, episode = $9, season = $10, image_url = $11, explicit = $12, thumbnail_url = $13,
+			content = $14
+		WHERE id = $15
+	`
+
+	executor := r.ExecExecutor()
+	tag, err := executor.Exec(
+		ctx,
+		query,
+		item.Title,
+		item.Description,
+		nullTime(item.PubDate),
+		item.Link,
+		item.Guid.Value,
+		item.Guid.IsPermaLink,
+		item.Author,
+		item.Duration,
+		item.Episode,
+		item.Season,
+		item.ImageUrl,
+		item.Explicit,
+		item.ThumbnailUrl,
+		item.Content,
+		id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to replace item with id=%d: %w", id, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrItemNotFound
+	}
+
+	for _, table := range []string{"enclosures", "attachments"} {
+		if _, err := executor.Exec(ctx, `DELETE FROM `+table+` WHERE item_id = $1`, id); err != nil {
+			return fmt.Errorf("failed to delete %v of item with id=%d: %w", table, id, err)
+		}
+	}
+
+	if err := saveMedia(ctx, executor, id, item); err != nil {
+		return err
+	}
+
+	return replaceCategories(ctx, r, "item_categories", "item_id", id, item.Categories)
+}
+
+// saveMedia stores the enclosures and attachments of an item.
+func saveMedia(ctx context.Context, executor storage.CommandExecutor, itemId int, item model.Item) error {
+	for _, e := range item.Enclosures {
+		query := `INSERT INTO enclosures (item_id, url, length, type) VALUES ($1, $2, $3, $4)`
+
+		if _, err := executor.Exec(ctx, query, itemId, e.Url, e.Length, e.Type); err != nil {
+			return fmt.Errorf("failed to save enclosure of item with id=%d: %w", itemId, err)
+		}
+	}
+
+	for _, a := range item.Attachments {
+		query := `
+			INSERT INTO attachments (item_id, url, type, medium, width, height, file_size, duration, description,
+				credit, thumbnail_url)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`
+
+		if _, err := executor.Exec(
+			ctx,
+			query,
+			itemId,
+			a.Url,
+			a.Type,
+			a.Medium,
+			a.Width,
+			a.Height,
+			a.FileSize,
+			a.Duration,
+			a.Description,
+			a.Credit,
+			a.ThumbnailUrl,
+		); err != nil {
+			return fmt.Errorf("failed to save attachment of item with id=%d: %w", itemId, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *ItemRepository) getItems(ctx context.Context, query string, args ...any) ([]model.Item, error) {
 	executor := r.QueryExecutor()
 
@@ -131,22 +423,12 @@ func (r *ItemRepository) getItems(ctx context.Context, query string, args ...any
 	var items []model.Item
 
 	for rows.Next() {
-		var (
-			id                 int
-			title, description string
-			pubDate            time.Time
-		)
-
-		if err := rows.Scan(&id, &title, &description, &pubDate); err != nil {
+		item, err := scanItem(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
 
-		items = append(items, model.Item{
-			Id:          id,
-			Title:       title,
-			Description: description,
-			PubDate:     model.DateTime(pubDate),
-		})
+		items = append(items, item)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -155,3 +437,70 @@ func (r *ItemRepository) getItems(ctx context.Context, query string, args ...any
 
 	return items, nil
 }
+
+// scanItem reads a row selected with itemColumns.
+func scanItem(row pgx.Row) (model.Item, error) {
+	var (
+		item        model.Item
+		pubDate     sql.NullTime
+		enclosures  []byte
+		attachments []byte
+		categories  []byte
+	)
+
+	if err := row.Scan(
+		&item.Id,
+		&item.Title,
+		&item.Description,
+		&pubDate,
+		&item.Link,
+		&item.Guid.Value,
+		&item.Guid.IsPermaLink,
+		&item.Author,
+		&item.Duration,
+		&item.Episode,
+		&item.Season,
+		&item.ImageUrl,
+		&item.Explicit,
+		&item.ThumbnailUrl,
+		&item.Content,
+		&enclosures,
+		&attachments,
+		&categories,
+	); err != nil {
+		return model.Item{}, err
+	}
+
+	item.PubDate = model.DateTime(pubDate.Time)
+
+	if err := json.Unmarshal(enclosures, &item.Enclosures); err != nil {
+		return model.Item{}, fmt.Errorf("failed to decode enclosures: %w", err)
+	}
+
+	if err := json.Unmarshal(attachments, &item.Attachments); err != nil {
+		return model.Item{}, fmt.Errorf("failed to decode attachments: %w", err)
+	}
+
+	// Items without media look the same as freshly parsed ones.
+	if len(item.Enclosures) == 0 {
+		item.Enclosures = nil
+	}
+
+	if len(item.Attachments) == 0 {
+		item.Attachments = nil
+	}
+
+	decoded, err := decodeCategories(categories)
+	if err != nil {
+		return model.Item{}, err
+	}
+
+	item.Categories = decoded
+
+	return item, nil
+}
+
+// nullTime maps an unknown date to NULL instead of the zero timestamp.
+func nullTime(dt model.DateTime) sql.NullTime {
+	return sql.NullTime{Time: time.Time(dt), Valid: !dt.IsZero()}
+}
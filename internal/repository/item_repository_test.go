@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -16,27 +18,75 @@ import (
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
-func TestItemRepository_Save(t *testing.T) {
+func TestItemRepository_SaveAll(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
-		id := 1
+		items := []model.Item{testutils.CreateItemWithId(1), testutils.CreateItemWithId(2)}
 
-		repo := setupItemRepositoryWithMockCommandExecutor(
-			func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
-				return pgconn.NewCommandTag(fmt.Sprintf("INSERT 0 %v", id)), nil
+		var (
+			inserted   [][]any
+			savedMedia [][]any
+		)
+
+		repo := setupItemRepositoryForSave(
+			func(args []any) (int, error) {
+				inserted = append(inserted, args)
+
+				return len(inserted), nil
+			},
+			func(args []any) error {
+				savedMedia = append(savedMedia, args)
+
+				return nil
 			})
 
-		err := repo.Save(context.Background(), testutils.CreateItemWithId(id), 1)
+		err := repo.SaveAll(context.Background(), items, 7)
+
+		require.NoError(t, err)
+		require.Len(t, inserted, 2)
+		require.Equal(t, items[1].Title, inserted[1][0])
+		require.Equal(t, 7, inserted[1][14])
+
+		a := items[0].Attachments[0]
+		require.Len(t, savedMedia, 6)
+		require.Equal(t, [][]any{
+			{1, "https://example.com/items/1.mp3", int64(1024), "audio/mpeg"},
+			{1, a.Url, a.Type, a.Medium, a.Width, a.Height, a.FileSize, a.Duration, a.Description, a.Credit,
+				a.ThumbnailUrl},
+			{1, []int{1}},
+		}, savedMedia[:3])
+		require.Equal(t, 2, savedMedia[3][0])
+	})
+
+	t.Run("NoItems", func(t *testing.T) {
+		repo := ItemRepositoryFactory{}.New(&mock.MockStorage{})
+
+		err := repo.SaveAll(context.Background(), nil, 1)
 
 		require.NoError(t, err)
 	})
 
 	t.Run("Fail", func(t *testing.T) {
-		repo := setupItemRepositoryWithMockCommandExecutor(
-			func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
-				return pgconn.NewCommandTag(""), errors.New("Executing failed")
+		repo := setupItemRepositoryForSave(
+			func(args []any) (int, error) {
+				return 0, errors.New("Inserting failed")
+			},
+			nil)
+
+		err := repo.SaveAll(context.Background(), []model.Item{testutils.CreateItemWithId(1)}, 1)
+
+		require.Error(t, err)
+	})
+
+	t.Run("EnclosureFail", func(t *testing.T) {
+		repo := setupItemRepositoryForSave(
+			func(args []any) (int, error) {
+				return 1, nil
+			},
+			func(args []any) error {
+				return errors.New("Executing failed")
 			})
 
-		err := repo.Save(context.Background(), testutils.CreateItemWithId(1), 1)
+		err := repo.SaveAll(context.Background(), []model.Item{testutils.CreateItemWithId(1)}, 1)
 
 		require.Error(t, err)
 	})
@@ -128,6 +178,112 @@ func TestItemRepository_GetByChannelId(t *testing.T) {
 	})
 }
 
+func TestItemRepository_GetByKeys(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.Item{testutils.CreateItemWithId(1)}
+
+		repo := setupItemRepositoryWithMockRows(expected)
+
+		keys := ItemKeys{Guids: []string{"item-1"}, Titles: []string{"Item 1"}}
+
+		actual, err := repo.GetByKeys(context.Background(), 1, keys)
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("FailQuery", func(t *testing.T) {
+		repo := setupItemRepositoryQueryFails(errors.New("Querying failed"))
+
+		actual, err := repo.GetByKeys(context.Background(), 1, ItemKeys{Guids: []string{"item-1"}})
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+}
+
+func TestItemRepository_GetByCategory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.Item{testutils.CreateItemWithId(1)}
+
+		repo := setupItemRepositoryWithMockRows(expected)
+
+		actual, err := repo.GetByCategory(context.Background(), "Golang")
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("FailQuery", func(t *testing.T) {
+		repo := setupItemRepositoryQueryFails(errors.New("Querying failed"))
+
+		actual, err := repo.GetByCategory(context.Background(), "golang")
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+}
+
+func TestItemRepository_GetCategories(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.CategoryCount{{Name: "Go", Count: 3}, {Name: "Rust", Count: 1}}
+
+		i := 0
+		mockRows := &mock.MockRows{
+			NextFunc: func() bool { return i < len(expected) },
+			ScanFunc: func(dest ...any) error {
+				*(dest[0].(*string)) = expected[i].Name //nolint:errcheck
+				*(dest[1].(*int)) = expected[i].Count   //nolint:errcheck
+				i++
+
+				return nil
+			},
+		}
+
+		mockStorage := &mock.MockStorage{
+			QueryExecutorFunc: &mock.MockRowQueryer{
+				QueryFunc: func(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+					return mockRows, nil
+				},
+			},
+		}
+
+		repo := ItemRepositoryFactory{}.New(mockStorage)
+
+		actual, err := repo.GetCategories(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("FailQuery", func(t *testing.T) {
+		repo := setupItemRepositoryQueryFails(errors.New("Querying failed"))
+
+		actual, err := repo.GetCategories(context.Background())
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("FailScan", func(t *testing.T) {
+		repo := setupItemRepositoryScanFails(errors.New("Scanning failed"))
+
+		actual, err := repo.GetCategories(context.Background())
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+
+	t.Run("IterationError", func(t *testing.T) {
+		repo := setupItemRepositoryIterationError(errors.New("Iteration error"))
+
+		actual, err := repo.GetCategories(context.Background())
+
+		require.Error(t, err)
+		require.Nil(t, actual)
+	})
+}
+
 func TestItemRepository_GetById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expected := testutils.CreateItemWithId(1)
@@ -144,6 +300,22 @@ func TestItemRepository_GetById(t *testing.T) {
 		require.Equal(t, expected, actual)
 	})
 
+	t.Run("NullPubDate", func(t *testing.T) {
+		expected := testutils.CreateItemWithId(1)
+		expected.PubDate = model.DateTime{}
+
+		repo := setupItemRepository(func(dest ...any) error {
+			fillDestWithItemTime(dest, expected)
+
+			return nil
+		})
+
+		actual, err := repo.GetById(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.True(t, actual.PubDate.IsZero())
+	})
+
 	t.Run("NotFound", func(t *testing.T) {
 		repo := setupItemRepository(func(dest ...any) error {
 			return pgx.ErrNoRows
@@ -209,7 +381,7 @@ func TestItemRepository_Update(t *testing.T) {
 		expected := testutils.CreateItemWithId(1)
 
 		repo := setupItemRepository(func(dest ...any) error {
-			fillDestWithItemModelTime(dest, expected)
+			fillDestWithItemTime(dest, expected)
 
 			return nil
 		})
@@ -219,6 +391,8 @@ func TestItemRepository_Update(t *testing.T) {
 			expected.Id,
 			expected.Title,
 			expected.Description,
+			expected.Link,
+			expected.Author,
 			time.Time(expected.PubDate),
 		)
 
@@ -238,6 +412,8 @@ func TestItemRepository_Update(t *testing.T) {
 			item.Id,
 			item.Title,
 			item.Description,
+			item.Link,
+			item.Author,
 			time.Time(item.PubDate),
 		)
 
@@ -257,6 +433,8 @@ func TestItemRepository_Update(t *testing.T) {
 			item.Id,
 			item.Title,
 			item.Description,
+			item.Link,
+			item.Author,
 			time.Time(item.PubDate),
 		)
 
@@ -265,6 +443,43 @@ func TestItemRepository_Update(t *testing.T) {
 	})
 }
 
+func TestItemRepository_Replace(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		item := testutils.CreateItemWithId(1)
+
+		repo := setupItemRepositoryWithMockCommandExecutor(
+			func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag("UPDATE 1"), nil
+			})
+
+		err := repo.Replace(context.Background(), item.Id, item)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("FailExec", func(t *testing.T) {
+		repo := setupItemRepositoryWithMockCommandExecutor(
+			func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), errors.New("Executing failed")
+			})
+
+		err := repo.Replace(context.Background(), 1, testutils.CreateItemWithId(1))
+
+		require.Error(t, err)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := setupItemRepositoryWithMockCommandExecutor(
+			func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+				return pgconn.NewCommandTag(""), nil
+			})
+
+		err := repo.Replace(context.Background(), 1, testutils.CreateItemWithId(1))
+
+		require.Equal(t, ErrItemNotFound, err)
+	})
+}
+
 func setupItemRepositoryWithMockCommandExecutor(
 	execFunc func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error),
 ) ItemRepositoryInterface {
@@ -273,7 +488,61 @@ func setupItemRepositoryWithMockCommandExecutor(
 	}
 
 	mockStorage := &mock.MockStorage{
-		ExecExecutorFunc: mockCommandExecutor,
+		QueryExecutorFunc: mockCategoryQueryer(),
+		ExecExecutorFunc:  mockCommandExecutor,
+	}
+
+	return ItemRepositoryFactory{}.New(mockStorage)
+}
+
+// setupItemRepositoryForSave answers the batches of SaveAll: insert returns
+// the id of an inserted item, exec executes the statements that refer to it.
+// Both are passed the arguments of the statement.
+func setupItemRepositoryForSave(
+	insert func(args []any) (int, error),
+	exec func(args []any) error,
+) ItemRepositoryInterface {
+	mockBatchSender := &mock.MockBatchSender{
+		SendBatchFunc: func(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+			i := 0
+			next := func() []any {
+				args := b.QueuedQueries[i].Arguments
+				i++
+
+				return args
+			}
+
+			return &mock.MockBatchResults{
+				QueryRowFunc: func() pgx.Row {
+					args := next()
+
+					return &mock.MockRow{ScanFunc: func(dest ...any) error {
+						id, err := insert(args)
+						*(dest[0].(*int)) = id //nolint:errcheck
+
+						return err
+					}}
+				},
+				ExecFunc: func() (pgconn.CommandTag, error) {
+					return pgconn.NewCommandTag("INSERT 0 1"), exec(next())
+				},
+				CloseFunc: func() error {
+					return nil
+				},
+			}
+		},
+	}
+
+	mockCommandExecutor := &mock.MockCommandExecutor{
+		ExecFunc: func(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+	}
+
+	mockStorage := &mock.MockStorage{
+		QueryExecutorFunc: mockCategoryQueryer(),
+		ExecExecutorFunc:  mockCommandExecutor,
+		BatchExecutorFunc: mockBatchSender,
 	}
 
 	return ItemRepositoryFactory{}.New(mockStorage)
@@ -380,15 +649,29 @@ func setupItemRepository(scanFunc func(dest ...any) error) ItemRepositoryInterfa
 }
 
 func fillDestWithItemTime(dest []any, item model.Item) {
-	*(dest[0].(*int)) = item.Id                       //nolint:errcheck
-	*(dest[1].(*string)) = item.Title                 //nolint:errcheck
-	*(dest[2].(*string)) = item.Description           //nolint:errcheck
-	*(dest[3].(*time.Time)) = time.Time(item.PubDate) //nolint:errcheck
-}
-
-func fillDestWithItemModelTime(dest []any, item model.Item) {
-	*(dest[0].(*int)) = item.Id                 //nolint:errcheck
-	*(dest[1].(*string)) = item.Title           //nolint:errcheck
-	*(dest[2].(*string)) = item.Description     //nolint:errcheck
-	*(dest[3].(*model.DateTime)) = item.PubDate //nolint:errcheck
+	*(dest[0].(*int)) = item.Id                //nolint:errcheck
+	*(dest[1].(*string)) = item.Title          //nolint:errcheck
+	*(dest[2].(*string)) = item.Description    //nolint:errcheck
+	*(dest[3].(*sql.NullTime)) = sql.NullTime{ //nolint:errcheck
+		Time:  time.Time(item.PubDate),
+		Valid: !item.PubDate.IsZero(),
+	}
+	*(dest[4].(*string)) = item.Link           //nolint:errcheck
+	*(dest[5].(*string)) = item.Guid.Value     //nolint:errcheck
+	*(dest[6].(*bool)) = item.Guid.IsPermaLink //nolint:errcheck
+	*(dest[7].(*string)) = item.Author         //nolint:errcheck
+	*(dest[8].(*int)) = item.Duration          //nolint:errcheck
+	*(dest[9].(*int)) = item.Episode           //nolint:errcheck
+	*(dest[10].(*int)) = item.Season           //nolint:errcheck
+	*(dest[11].(*string)) = item.ImageUrl      //nolint:errcheck
+	*(dest[12].(*bool)) = item.Explicit        //nolint:errcheck
+	*(dest[13].(*string)) = item.ThumbnailUrl  //nolint:errcheck
+	*(dest[14].(*string)) = item.Content       //nolint:errcheck
+
+	enclosures, _ := json.Marshal(item.Enclosures)   //nolint:errchkjson
+	*(dest[15].(*[]byte)) = enclosures               //nolint:errcheck
+	attachments, _ := json.Marshal(item.Attachments) //nolint:errchkjson
+	*(dest[16].(*[]byte)) = attachments              //nolint:errcheck
+	categories, _ := json.Marshal(item.Categories)   //nolint:errchkjson
+	*(dest[17].(*[]byte)) = categories               //nolint:errcheck
 }
@@ -0,0 +1,61 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/marchuknikolay/rss-parser/internal/testutils"
+)
+
+type MockBatchSender struct {
+	SendBatchFunc func(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+func (m MockBatchSender) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	if m.SendBatchFunc != nil {
+		return m.SendBatchFunc(ctx, b)
+	}
+
+	return MockBatchResults{}
+}
+
+type MockBatchResults struct {
+	ExecFunc     func() (pgconn.CommandTag, error)
+	QueryFunc    func() (pgx.Rows, error)
+	QueryRowFunc func() pgx.Row
+	CloseFunc    func() error
+}
+
+func (m MockBatchResults) Exec() (pgconn.CommandTag, error) {
+	if m.ExecFunc != nil {
+		return m.ExecFunc()
+	}
+
+	return pgconn.CommandTag{}, testutils.ErrNotImplemented
+}
+
+func (m MockBatchResults) Query() (pgx.Rows, error) {
+	if m.QueryFunc != nil {
+		return m.QueryFunc()
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
+func (m MockBatchResults) QueryRow() pgx.Row {
+	if m.QueryRowFunc != nil {
+		return m.QueryRowFunc()
+	}
+
+	return MockRow{}
+}
+
+func (m MockBatchResults) Close() error {
+	if m.CloseFunc != nil {
+		return m.CloseFunc()
+	}
+
+	return testutils.ErrNotImplemented
+}
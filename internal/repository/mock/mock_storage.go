@@ -10,6 +10,7 @@ import (
 type MockStorage struct {
 	QueryExecutorFunc storage.RowQueryer
 	ExecExecutorFunc  storage.CommandExecutor
+	BatchExecutorFunc storage.BatchSender
 
 	WithTransactionFunc func(ctx context.Context, fn func(txStorage storage.Interface) error) error
 }
@@ -22,6 +23,10 @@ func (m MockStorage) ExecExecutor() storage.CommandExecutor {
 	return m.ExecExecutorFunc
 }
 
+func (m MockStorage) BatchExecutor() storage.BatchSender {
+	return m.BatchExecutorFunc
+}
+
 func (m MockStorage) WithTransaction(ctx context.Context, fn func(txStorage storage.Interface) error) error {
 	if m.WithTransactionFunc != nil {
 		return m.WithTransactionFunc(ctx, fn)
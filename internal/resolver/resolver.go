@@ -0,0 +1,196 @@
+package resolver
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// urlAttributes are the HTML attributes that hold a single URL.
+func urlAttributes() []string {
+	return []string{"href", "src", "poster", "cite", "data", "action", "longdesc", "background"}
+}
+
+// Channel rewrites the relative URLs of a channel and its items, including
+// the ones in item HTML, to absolute URLs. They are resolved against the
+// xml:base in scope, or the URL the feed was fetched from. Item HTML without
+// an xml:base of its own is resolved against the item link, since that is
+// the page it was taken from.
+func Channel(channel *model.Channel, feedUrl string) {
+	base := channelBase(channel, feedUrl)
+
+	channel.Link = resolve(base, channel.Link)
+	channel.ImageUrl = resolve(base, channel.ImageUrl)
+
+	for i := range channel.Items {
+		resolveItem(&channel.Items[i], base)
+	}
+}
+
+// Item rewrites the relative URLs of an item of channel like Channel does,
+// for items that are resolved apart from their channel.
+func Item(item *model.Item, channel *model.Channel, feedUrl string) {
+	resolveItem(item, channelBase(channel, feedUrl))
+}
+
+// channelBase returns the URL the channel is relative to.
+func channelBase(channel *model.Channel, feedUrl string) *url.URL {
+	base, err := url.Parse(strings.TrimSpace(feedUrl))
+	if err != nil {
+		base = &url.URL{}
+	}
+
+	return withXmlBase(base, channel.XmlBase)
+}
+
+func resolveItem(item *model.Item, channelBase *url.URL) {
+	base := withXmlBase(channelBase, item.XmlBase)
+
+	item.Link = resolve(base, item.Link)
+	item.ImageUrl = resolve(base, item.ImageUrl)
+	item.ThumbnailUrl = resolve(base, item.ThumbnailUrl)
+
+	for i := range item.Enclosures {
+		item.Enclosures[i].Url = resolve(base, item.Enclosures[i].Url)
+	}
+
+	for i := range item.Attachments {
+		item.Attachments[i].Url = resolve(base, item.Attachments[i].Url)
+		item.Attachments[i].ThumbnailUrl = resolve(base, item.Attachments[i].ThumbnailUrl)
+	}
+
+	contentBase := base
+	if strings.TrimSpace(item.XmlBase) == "" {
+		if link, err := url.Parse(item.Link); err == nil && link.IsAbs() {
+			contentBase = link
+		}
+	}
+
+	item.Description = resolveHtml(contentBase, item.Description)
+	item.Content = resolveHtml(contentBase, item.Content)
+}
+
+// withXmlBase applies an xml:base, which may itself be relative, to base.
+func withXmlBase(base *url.URL, xmlBase string) *url.URL {
+	ref, err := url.Parse(strings.TrimSpace(xmlBase))
+	if err != nil || ref.String() == "" {
+		return base
+	}
+
+	return base.ResolveReference(ref)
+}
+
+// resolve returns ref as an absolute URL. References that are absolute
+// already, point within the same document or can't be resolved are kept.
+func resolve(base *url.URL, ref string) string {
+	trimmed := strings.TrimSpace(ref)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || !base.IsAbs() {
+		return ref
+	}
+
+	u, err := url.Parse(trimmed)
+	if err != nil || u.IsAbs() {
+		return ref
+	}
+
+	return base.ResolveReference(u).String()
+}
+
+// resolveHtml rewrites the URL attributes of an HTML fragment. Tags without
+// relative URLs are copied as they are, so the rest of the markup is kept
+// byte for byte.
+func resolveHtml(base *url.URL, fragment string) string {
+	if fragment == "" || !base.IsAbs() {
+		return fragment
+	}
+
+	var b strings.Builder
+
+	z := html.NewTokenizer(strings.NewReader(fragment))
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+
+		// Token lowercases the tag in the tokenizer buffer, so the raw markup
+		// has to be copied first.
+		raw := string(z.Raw())
+
+		if tt == html.StartTagToken || tt == html.SelfClosingTagToken {
+			tok := z.Token()
+			if resolveAttributes(base, tok.Attr) {
+				b.WriteString(tok.String())
+
+				continue
+			}
+		}
+
+		b.WriteString(raw)
+	}
+
+	return b.String()
+}
+
+// resolveAttributes resolves the URL attributes in place and reports whether
+// any of them changed.
+func resolveAttributes(base *url.URL, attrs []html.Attribute) bool {
+	changed := false
+
+	for i, a := range attrs {
+		var resolved string
+
+		switch {
+		case a.Namespace != "":
+			continue
+		case a.Key == "srcset":
+			resolved = resolveSrcset(base, a.Val)
+		case slices.Contains(urlAttributes(), a.Key):
+			resolved = resolve(base, a.Val)
+		default:
+			continue
+		}
+
+		if resolved != a.Val {
+			attrs[i].Val = resolved
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// resolveSrcset resolves the image candidates of a srcset attribute, e.g.
+// "a.png 1x, b.png 2x".
+func resolveSrcset(base *url.URL, srcset string) string {
+	candidates := strings.Split(srcset, ",")
+	changed := false
+
+	for i, c := range candidates {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if resolved := resolve(base, fields[0]); resolved != fields[0] {
+			fields[0] = resolved
+			candidates[i] = strings.Join(fields, " ")
+			changed = true
+		}
+	}
+
+	if !changed {
+		return srcset
+	}
+
+	for i := range candidates {
+		candidates[i] = strings.TrimSpace(candidates[i])
+	}
+
+	return strings.Join(candidates, ", ")
+}
@@ -0,0 +1,108 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+const feedUrl = "https://example.com/blog/feed.xml"
+
+func TestChannel(t *testing.T) {
+	t.Run("FeedUrl", func(t *testing.T) {
+		channel := model.Channel{
+			Link:     "/blog/",
+			ImageUrl: "logo.png",
+			Items: []model.Item{{
+				Link:        "posts/1",
+				Description: `<p>See <a href="/about">about</a> and <img src="a.png" alt="A"></p>`,
+				Enclosures:  []model.Enclosure{{Url: "1.mp3"}},
+				Attachments: []model.Attachment{{Url: "1.mp4", ThumbnailUrl: "//cdn.example.com/1.jpg"}},
+			}},
+		}
+
+		Channel(&channel, feedUrl)
+
+		require.Equal(t, "https://example.com/blog/", channel.Link)
+		require.Equal(t, "https://example.com/blog/logo.png", channel.ImageUrl)
+
+		item := channel.Items[0]
+		require.Equal(t, "https://example.com/blog/posts/1", item.Link)
+		require.Equal(t,
+			`<p>See <a href="https://example.com/about">about</a> and `+
+				`<img src="https://example.com/blog/posts/a.png" alt="A"></p>`,
+			item.Description)
+		require.Equal(t, "https://example.com/blog/1.mp3", item.Enclosures[0].Url)
+		require.Equal(t, "https://example.com/blog/1.mp4", item.Attachments[0].Url)
+		require.Equal(t, "https://cdn.example.com/1.jpg", item.Attachments[0].ThumbnailUrl)
+	})
+
+	t.Run("XmlBase", func(t *testing.T) {
+		channel := model.Channel{
+			XmlBase: "https://static.example.org/",
+			Items: []model.Item{
+				{
+					Link:    "https://example.com/posts/1",
+					XmlBase: "posts/1/",
+					Content: `<img src="photo.jpg">`,
+				},
+				{
+					Link:    "https://example.com/posts/2",
+					Content: `<img src="photo.jpg">`,
+				},
+			},
+		}
+
+		Channel(&channel, feedUrl)
+
+		require.Equal(t, `<img src="https://static.example.org/posts/1/photo.jpg">`, channel.Items[0].Content)
+		// Without an xml:base of its own, the content is relative to the item link.
+		require.Equal(t, `<img src="https://example.com/posts/photo.jpg">`, channel.Items[1].Content)
+	})
+
+	t.Run("KeepsAbsoluteAndFragmentUrls", func(t *testing.T) {
+		content := `<a href="#notes">Notes</a> <a HREF="mailto:me@example.com">Mail</a>` +
+			`<img src="https://example.org/a.png"><script>var s = "<a href='/x'>";</script>`
+
+		channel := model.Channel{Items: []model.Item{{Link: "https://example.com/posts/1", Content: content}}}
+
+		Channel(&channel, feedUrl)
+
+		require.Equal(t, content, channel.Items[0].Content)
+	})
+
+	t.Run("Srcset", func(t *testing.T) {
+		channel := model.Channel{Items: []model.Item{{
+			Link:    "https://example.com/posts/1",
+			Content: `<img srcset="small.png 1x,/large.png 2x" src="https://example.com/small.png">`,
+		}}}
+
+		Channel(&channel, feedUrl)
+
+		require.Equal(t,
+			`<img srcset="https://example.com/posts/small.png 1x, https://example.com/large.png 2x" `+
+				`src="https://example.com/small.png">`,
+			channel.Items[0].Content)
+	})
+
+	t.Run("NoFeedUrl", func(t *testing.T) {
+		channel := model.Channel{Items: []model.Item{{Link: "/posts/1", Description: `<img src="a.png">`}}}
+
+		Channel(&channel, "")
+
+		require.Equal(t, "/posts/1", channel.Items[0].Link)
+		require.Equal(t, `<img src="a.png">`, channel.Items[0].Description)
+	})
+}
+
+func TestItem(t *testing.T) {
+	channel := model.Channel{XmlBase: "/static/"}
+	item := model.Item{Link: "posts/1", ThumbnailUrl: "1.jpg"}
+
+	Item(&item, &channel, feedUrl)
+
+	require.Equal(t, "https://example.com/static/posts/1", item.Link)
+	require.Equal(t, "https://example.com/static/1.jpg", item.ThumbnailUrl)
+}
@@ -0,0 +1,45 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/service"
+	"github.com/marchuknikolay/rss-parser/internal/testutils"
+)
+
+type MockService struct {
+	GetChannelsFunc        func(ctx context.Context) ([]model.Channel, error)
+	ImportFeedFunc         func(ctx context.Context, url string) (service.ImportStats, error)
+	RecordFetchFailureFunc func(ctx context.Context, url string, failedAt, nextFetchAt time.Time, reason string) error
+}
+
+func (m *MockService) GetChannels(ctx context.Context) ([]model.Channel, error) {
+	if m.GetChannelsFunc != nil {
+		return m.GetChannelsFunc(ctx)
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
+func (m *MockService) ImportFeed(ctx context.Context, url string) (service.ImportStats, error) {
+	if m.ImportFeedFunc != nil {
+		return m.ImportFeedFunc(ctx, url)
+	}
+
+	return service.ImportStats{}, testutils.ErrNotImplemented
+}
+
+func (m *MockService) RecordFetchFailure(
+	ctx context.Context,
+	url string,
+	failedAt, nextFetchAt time.Time,
+	reason string,
+) error {
+	if m.RecordFetchFailureFunc != nil {
+		return m.RecordFetchFailureFunc(ctx, url, failedAt, nextFetchAt, reason)
+	}
+
+	return testutils.ErrNotImplemented
+}
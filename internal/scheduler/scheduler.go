@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/service"
+)
+
+type ServiceInterface interface {
+	GetChannels(ctx context.Context) ([]model.Channel, error)
+	ImportFeed(ctx context.Context, url string) (service.ImportStats, error)
+	RecordFetchFailure(ctx context.Context, url string, failedAt, nextFetchAt time.Time, reason string) error
+}
+
+// checkPeriod is how often the scheduler looks for due channels, unless the
+// global interval is even shorter.
+const checkPeriod = time.Minute
+
+// Scheduler periodically re-imports every stored channel from its source URL
+// once it is due. A successful import plans the next refresh of a channel
+// from the global interval and the publisher's polling hints, see
+// service.WithRefreshInterval; a failed one is retried after the interval.
+type Scheduler struct {
+	service  ServiceInterface
+	interval time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// ErrInvalidInterval is returned by New for an interval that isn't positive.
+var ErrInvalidInterval = errors.New("the refresh interval must be positive")
+
+func New(svc ServiceInterface, interval time.Duration) (*Scheduler, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w, got %v", ErrInvalidInterval, interval)
+	}
+
+	return &Scheduler{
+		service:  svc,
+		interval: interval,
+	}, nil
+}
+
+// Start runs the refresh loop in the background until Shutdown is called.
+func (s *Scheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+}
+
+// Shutdown stops the refresh loop, aborting an in-progress refresh, and waits
+// for it to exit or for ctx to expire.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	if s.cancel == nil {
+		return nil
+	}
+
+	s.cancel()
+
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(min(s.interval, checkPeriod))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+func (s *Scheduler) refresh(ctx context.Context) {
+	channels, err := s.service.GetChannels(ctx)
+	if err != nil {
+		log.Printf("Failed getting channels to refresh: %v", err)
+
+		return
+	}
+
+	now := time.Now()
+
+	for _, ch := range dueFeeds(channels, now) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		s.refreshFeed(ctx, ch.SourceUrl)
+	}
+}
+
+// refreshFeed imports the feed at url, which records the fetch and plans the
+// next one when it succeeds.
+func (s *Scheduler) refreshFeed(ctx context.Context, url string) {
+	_, err := s.service.ImportFeed(ctx, url)
+	if err == nil {
+		return
+	}
+
+	// Failures caused by shutting down say nothing about the feed.
+	if ctx.Err() != nil {
+		return
+	}
+
+	log.Printf("Failed refreshing feed %v: %v", url, err)
+
+	now := time.Now()
+
+	if err := s.service.RecordFetchFailure(ctx, url, now, now.Add(s.interval), err.Error()); err != nil {
+		log.Printf("Failed recording fetch failure for %v: %v", url, err)
+	}
+}
+
+// dueFeeds returns one channel per source URL that is due for a refresh at
+// now. Channels imported before source URLs were stored have none and can't
+// be refreshed.
+func dueFeeds(channels []model.Channel, now time.Time) []model.Channel {
+	seen := make(map[string]struct{}, len(channels))
+	due := make([]model.Channel, 0, len(channels))
+
+	for _, ch := range channels {
+		if ch.SourceUrl == "" {
+			continue
+		}
+
+		if _, ok := seen[ch.SourceUrl]; ok {
+			continue
+		}
+
+		seen[ch.SourceUrl] = struct{}{}
+
+		if ch.NextFetchAt.IsZero() || !now.Before(time.Time(ch.NextFetchAt)) {
+			due = append(due, ch)
+		}
+	}
+
+	return due
+}
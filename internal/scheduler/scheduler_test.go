@@ -0,0 +1,153 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/scheduler/mock"
+	"github.com/marchuknikolay/rss-parser/internal/service"
+	"github.com/marchuknikolay/rss-parser/internal/testutils"
+)
+
+func newScheduler(t *testing.T, svc ServiceInterface, interval time.Duration) *Scheduler {
+	t.Helper()
+
+	s, err := New(svc, interval)
+	require.NoError(t, err)
+
+	return s
+}
+
+func TestNew(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		_, err := New(&mock.MockService{}, interval)
+
+		require.ErrorIs(t, err, ErrInvalidInterval)
+	}
+}
+
+func TestScheduler_Refresh(t *testing.T) {
+	t.Run("RecordsFailures", func(t *testing.T) {
+		ok := testutils.CreateChannelWithId(1)
+		failing := testutils.CreateChannelWithId(2)
+		legacy := testutils.CreateChannelWithId(3)
+		legacy.SourceUrl = ""
+
+		var (
+			imported []string
+			failed   = map[string]string{}
+		)
+
+		mockService := &mock.MockService{
+			GetChannelsFunc: func(ctx context.Context) ([]model.Channel, error) {
+				return []model.Channel{ok, failing, ok, legacy}, nil
+			},
+			ImportFeedFunc: func(ctx context.Context, url string) (service.ImportStats, error) {
+				imported = append(imported, url)
+
+				if url == failing.SourceUrl {
+					return service.ImportStats{}, errors.New("unexpected status code: 500")
+				}
+
+				return service.ImportStats{Unchanged: 1}, nil
+			},
+			RecordFetchFailureFunc: func(ctx context.Context, url string, failedAt, next time.Time, reason string) error {
+				failed[url] = reason
+
+				return nil
+			},
+		}
+
+		newScheduler(t, mockService, time.Hour).refresh(context.Background())
+
+		require.Equal(t, []string{ok.SourceUrl, failing.SourceUrl}, imported)
+		require.Equal(t, map[string]string{failing.SourceUrl: "unexpected status code: 500"}, failed)
+	})
+
+	t.Run("SkipsChannelsNotDue", func(t *testing.T) {
+		due := testutils.CreateChannelWithId(1)
+		due.NextFetchAt = model.DateTime(time.Now().Add(-time.Minute))
+
+		notDue := testutils.CreateChannelWithId(2)
+		notDue.NextFetchAt = model.DateTime(time.Now().Add(time.Hour))
+
+		var imported []string
+
+		mockService := &mock.MockService{
+			GetChannelsFunc: func(ctx context.Context) ([]model.Channel, error) {
+				return []model.Channel{due, notDue}, nil
+			},
+			ImportFeedFunc: func(ctx context.Context, url string) (service.ImportStats, error) {
+				imported = append(imported, url)
+
+				return service.ImportStats{}, nil
+			},
+		}
+
+		newScheduler(t, mockService, time.Hour).refresh(context.Background())
+
+		require.Equal(t, []string{due.SourceUrl}, imported)
+	})
+
+	t.Run("GetChannelsFailed", func(t *testing.T) {
+		imported := false
+
+		mockService := &mock.MockService{
+			GetChannelsFunc: func(ctx context.Context) ([]model.Channel, error) {
+				return nil, errors.New("Querying failed")
+			},
+			ImportFeedFunc: func(ctx context.Context, url string) (service.ImportStats, error) {
+				imported = true
+
+				return service.ImportStats{}, nil
+			},
+		}
+
+		newScheduler(t, mockService, time.Hour).refresh(context.Background())
+
+		require.False(t, imported)
+	})
+}
+
+func TestScheduler_StartAndShutdown(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+
+	refreshed := make(chan struct{})
+
+	mockService := &mock.MockService{
+		GetChannelsFunc: func(ctx context.Context) ([]model.Channel, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls++
+			if calls == 1 {
+				close(refreshed)
+			}
+
+			return nil, nil
+		},
+	}
+
+	s := newScheduler(t, mockService, time.Millisecond)
+	s.Start()
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not refresh")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	require.NoError(t, s.Shutdown(ctx))
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/server/templates/constants"
+)
+
+// Font sizes of the least and the most used categories in the cloud, in
+// percent of the normal size.
+const (
+	minCloudFontSize = 100
+	maxCloudFontSize = 250
+)
+
+type categoryView struct {
+	Name     string
+	Count    int
+	FontSize int
+}
+
+func (h *Handler) getCategories(c echo.Context) error {
+	categories, err := h.service.GetCategories(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get categories: "+err.Error())
+	}
+
+	return c.Render(http.StatusOK, constants.CategoriesTemplate, categoryCloud(categories))
+}
+
+// categoryCloud sizes each category linearly between the least and the most
+// used ones.
+func categoryCloud(categories []model.CategoryCount) []categoryView {
+	if len(categories) == 0 {
+		return nil
+	}
+
+	least, most := categories[0].Count, categories[0].Count
+
+	for _, c := range categories {
+		least = min(least, c.Count)
+		most = max(most, c.Count)
+	}
+
+	views := make([]categoryView, 0, len(categories))
+
+	for _, c := range categories {
+		fontSize := minCloudFontSize
+		if most > least {
+			fontSize += (maxCloudFontSize - minCloudFontSize) * (c.Count - least) / (most - least)
+		}
+
+		views = append(views, categoryView{Name: c.Name, Count: c.Count, FontSize: fontSize})
+	}
+
+	return views
+}
@@ -1,17 +1,27 @@
 package handlers
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"log"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 
+	"github.com/marchuknikolay/rss-parser/internal/opml"
 	"github.com/marchuknikolay/rss-parser/internal/repository"
 	"github.com/marchuknikolay/rss-parser/internal/server/templates/constants"
+	"github.com/marchuknikolay/rss-parser/internal/service"
 )
 
+// maxOpmlSize is far more than a subscription list of thousands of feeds.
+const maxOpmlSize = 5 << 20
+
 func (h *Handler) importFeeds(c echo.Context) error {
 	rawUrls := c.FormValue("urls")
 	if rawUrls == "" {
@@ -19,22 +29,143 @@ func (h *Handler) importFeeds(c echo.Context) error {
 	}
 
 	lines := strings.Split(rawUrls, "\n")
-	urls := make([]string, 0, len(lines))
+	subscriptions := make([]service.Subscription, 0, len(lines))
 	for _, line := range lines {
 		if url := strings.TrimSpace(line); url != "" {
-			urls = append(urls, url)
+			subscriptions = append(subscriptions, service.Subscription{Url: url})
 		}
 	}
 
-	if len(urls) == 0 {
+	if len(subscriptions) == 0 {
 		return echo.NewHTTPError(http.StatusBadRequest, "No valid URLs provided")
 	}
 
-	if err := h.service.ImportFeeds(c.Request().Context(), urls); err != nil {
+	report, err := h.service.ImportFeeds(c.Request().Context(), subscriptions)
+
+	var importErr *service.ImportError
+	if err != nil && !errors.As(err, &importErr) {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import feeds: "+err.Error())
 	}
 
-	return c.Render(http.StatusOK, constants.MessageTemplate, struct{ Message string }{Message: "Import successful!"})
+	status := importStatus(report)
+
+	if acceptsJson(c) {
+		return c.JSON(status, report)
+	}
+
+	return c.Render(status, constants.MessageTemplate, messageView{Message: importMessage(report), Feeds: report.Feeds})
+}
+
+// messageView is a message with the results of the feeds it is about, if any.
+type messageView struct {
+	Message string
+	Feeds   []service.FeedResult
+}
+
+// importStatus is 200 OK as long as one of the feeds was imported, the
+// results tell which ones failed. When all of them failed, URLs refused
+// because of what they serve are the client's mistake.
+func importStatus(report service.ImportReport) int {
+	status := http.StatusInternalServerError
+
+	for _, feed := range report.Feeds {
+		switch feed.ErrorCategory {
+		case "":
+			return http.StatusOK
+		case service.ErrorCategoryRejected, service.ErrorCategoryNotFound:
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	return status
+}
+
+func importMessage(report service.ImportReport) string {
+	imported := 0
+
+	for _, feed := range report.Feeds {
+		if feed.Err == nil {
+			imported++
+		}
+	}
+
+	stats := report.Total
+
+	message := "Import successful!"
+	if imported < len(report.Feeds) {
+		message = fmt.Sprintf("Imported %v of %v feeds.", imported, len(report.Feeds))
+	}
+
+	message += fmt.Sprintf(
+		" Items inserted: %v, updated: %v, unchanged: %v.",
+		stats.Inserted,
+		stats.Updated,
+		stats.Unchanged,
+	)
+
+	for _, d := range stats.Discovered {
+		message += fmt.Sprintf(" Imported %v as the feed of %v.", d.FeedUrl, d.PageUrl)
+	}
+
+	return message
+}
+
+// importOpml imports the feeds of an uploaded OPML subscription list. The
+// report lists the outcome of each feed, the status is that of importFeeds.
+func (h *Handler) importOpml(c echo.Context) error {
+	file, err := c.FormFile("opml")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing 'opml' file")
+	}
+
+	if file.Size > maxOpmlSize {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "OPML file is too large")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to open OPML file: "+err.Error())
+	}
+
+	defer func() {
+		if err := src.Close(); err != nil {
+			log.Printf("Failed to close OPML file: %v", err)
+		}
+	}()
+
+	bs, err := io.ReadAll(io.LimitReader(src, maxOpmlSize))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read OPML file: "+err.Error())
+	}
+
+	feeds, err := opml.Parse(bs)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid OPML file: "+err.Error())
+	}
+
+	if len(feeds) == 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "No feeds found in OPML file")
+	}
+
+	subscriptions := make([]service.Subscription, 0, len(feeds))
+	for _, feed := range feeds {
+		subscriptions = append(subscriptions, service.Subscription{Url: feed.XmlUrl, Folder: feed.Folder})
+	}
+
+	report, err := h.service.ImportFeeds(c.Request().Context(), subscriptions)
+
+	var importErr *service.ImportError
+	if err != nil && !errors.As(err, &importErr) {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to import feeds: "+err.Error())
+	}
+
+	status := importStatus(report)
+
+	if acceptsJson(c) {
+		return c.JSON(status, report)
+	}
+
+	return c.Render(status, constants.ImportReportTemplate, report)
 }
 
 func (h *Handler) getChannels(c echo.Context) error {
@@ -46,6 +177,25 @@ func (h *Handler) getChannels(c echo.Context) error {
 	return c.Render(http.StatusOK, constants.ChannelsTemplate, channels)
 }
 
+// exportOpml returns every subscription as an OPML 2.0 document, so it can be
+// imported back with importOpml or into another feed reader.
+func (h *Handler) exportOpml(c echo.Context) error {
+	channels, err := h.service.GetChannels(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get channels: "+err.Error())
+	}
+
+	var buf bytes.Buffer
+
+	if err := opml.Write(&buf, opml.ExportTitle, opml.FromChannels(channels), time.Now()); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to export channels: "+err.Error())
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="subscriptions.opml"`)
+
+	return c.Blob(http.StatusOK, "text/x-opml; charset=utf-8", buf.Bytes())
+}
+
 func (h *Handler) deleteChannel(c echo.Context) error {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"html/template"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -25,7 +26,9 @@ func (h *Handler) InitRoutes() (*echo.Echo, error) {
 	router := echo.New()
 
 	funcMap := template.FuncMap{
-		"formatDate": funcs.FormatDate,
+		"formatDate":     funcs.FormatDate,
+		"formatDuration": funcs.FormatDuration,
+		"mediaKind":      funcs.MediaKind,
 	}
 
 	r, err := renderer.New("internal/server/templates/", &funcMap)
@@ -42,7 +45,9 @@ func (h *Handler) InitRoutes() (*echo.Echo, error) {
 
 	channels := router.Group("/channels")
 	channels.POST("/", h.importFeeds)
+	channels.POST("/opml/", h.importOpml)
 	channels.GET("/", h.getChannels)
+	channels.GET("/export.opml/", h.exportOpml)
 	channels.GET("/:id/", h.getItemsByChannelId)
 	channels.PUT("/:id/", h.updateChannel)
 	channels.DELETE("/:id/", h.deleteChannel)
@@ -53,5 +58,13 @@ func (h *Handler) InitRoutes() (*echo.Echo, error) {
 	items.DELETE("/:id/", h.deleteItem)
 	items.PUT("/:id/", h.updateItem)
 
+	router.GET("/categories/", h.getCategories)
+	router.POST("/validate/", h.validateFeed)
+
 	return router, nil
 }
+
+// acceptsJson reports whether the client asked for JSON rather than a page.
+func acceptsJson(c echo.Context) bool {
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMEApplicationJSON)
+}
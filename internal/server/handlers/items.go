@@ -19,10 +19,32 @@ type itemView struct {
 	Title       string
 	PubDate     model.DateTime
 	Description template.HTML
+	Link        string
+	Author      string
+	Enclosures  []model.Enclosure
+	Duration    int
+	Episode     int
+	Season      int
+	ImageUrl    string
+	Explicit    bool
+	Attachments []model.Attachment
+	Categories  []model.Category
 }
 
+// getItems lists all items, or only the ones filed under the category given
+// in the query string.
 func (h *Handler) getItems(c echo.Context) error {
-	items, err := h.service.GetItems(c.Request().Context())
+	var (
+		items []model.Item
+		err   error
+	)
+
+	if category := c.QueryParam("category"); category != "" {
+		items, err = h.service.GetItemsByCategory(c.Request().Context(), category)
+	} else {
+		items, err = h.service.GetItems(c.Request().Context())
+	}
+
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get items: "+err.Error())
 	}
@@ -61,13 +83,29 @@ func (h *Handler) getItemById(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get item: "+err.Error())
 	}
 
+	// The item page shows the full article, list views only the summary.
+	body := item.Content
+	if body == "" {
+		body = item.Description
+	}
+
 	policy := bluemonday.UGCPolicy()
-	safeHTML := policy.Sanitize(item.Description)
+	safeHTML := policy.Sanitize(body)
 
 	view := itemView{
 		Title:       item.Title,
 		PubDate:     item.PubDate,
 		Description: template.HTML(safeHTML),
+		Link:        item.Link,
+		Author:      item.Author,
+		Enclosures:  item.Enclosures,
+		Duration:    item.Duration,
+		Episode:     item.Episode,
+		Season:      item.Season,
+		ImageUrl:    item.ImageUrl,
+		Explicit:    item.Explicit,
+		Attachments: item.Attachments,
+		Categories:  item.Categories,
 	}
 
 	return c.Render(http.StatusOK, constants.ItemTemplate, view)
@@ -102,6 +140,8 @@ func (h *Handler) updateItem(c echo.Context) error {
 	var input struct {
 		Title       string `json:"title"`
 		Description string `json:"description"`
+		Link        string `json:"link"`
+		Author      string `json:"author"`
 		PubDate     string `json:"pub_date"`
 	}
 
@@ -115,7 +155,15 @@ func (h *Handler) updateItem(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid pub_date format: "+input.PubDate)
 	}
 
-	updatedItem, err := h.service.UpdateItem(c.Request().Context(), id, input.Title, input.Description, pubDate)
+	updatedItem, err := h.service.UpdateItem(
+		c.Request().Context(),
+		id,
+		input.Title,
+		input.Description,
+		input.Link,
+		input.Author,
+		pubDate,
+	)
 	if err != nil {
 		if errors.Is(err, repository.ErrItemNotFound) {
 			return echo.NewHTTPError(http.StatusNotFound, "Item not found")
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/marchuknikolay/rss-parser/internal/fetcher"
+	"github.com/marchuknikolay/rss-parser/internal/server/templates/constants"
+)
+
+// validateFeed reports what is wrong with the feed at the given URL without
+// importing it. The report is rendered as a page, or returned as JSON to
+// clients that accept it.
+func (h *Handler) validateFeed(c echo.Context) error {
+	url := strings.TrimSpace(c.FormValue("url"))
+	if url == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing 'url' parameter")
+	}
+
+	report, err := h.service.ValidateFeed(c.Request().Context(), url)
+	if err != nil {
+		var rejected *fetcher.RejectedError
+		if errors.As(err, &rejected) {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, "Failed to fetch feed: "+err.Error())
+		}
+
+		return echo.NewHTTPError(http.StatusBadGateway, "Failed to fetch feed: "+err.Error())
+	}
+
+	if acceptsJson(c) {
+		return c.JSON(http.StatusOK, report)
+	}
+
+	return c.Render(http.StatusOK, constants.ValidationReportTemplate, report)
+}
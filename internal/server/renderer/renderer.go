@@ -55,6 +55,24 @@ func New(path string, funcMap *template.FuncMap) (*Renderer, error) {
 		return nil, fmt.Errorf("load message template: %w", err)
 	}
 
+	if tmpls[constants.ImportReportTemplate], err = loadTemplate(
+		filepath.Join(path, constants.BaseTemplate),
+		filepath.Join(path, constants.ImportReportTemplate)); err != nil {
+		return nil, fmt.Errorf("load import report template: %w", err)
+	}
+
+	if tmpls[constants.CategoriesTemplate], err = loadTemplate(
+		filepath.Join(path, constants.BaseTemplate),
+		filepath.Join(path, constants.CategoriesTemplate)); err != nil {
+		return nil, fmt.Errorf("load categories template: %w", err)
+	}
+
+	if tmpls[constants.ValidationReportTemplate], err = loadTemplate(
+		filepath.Join(path, constants.BaseTemplate),
+		filepath.Join(path, constants.ValidationReportTemplate)); err != nil {
+		return nil, fmt.Errorf("load validation report template: %w", err)
+	}
+
 	return &Renderer{templates: tmpls}, nil
 }
 
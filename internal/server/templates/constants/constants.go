@@ -6,4 +6,9 @@ const (
 	ItemsTemplate    = "items.gohtml"
 	ItemTemplate     = "item.gohtml"
 	MessageTemplate  = "message.gohtml"
+
+	ImportReportTemplate = "import_report.gohtml"
+	CategoriesTemplate   = "categories.gohtml"
+
+	ValidationReportTemplate = "validation_report.gohtml"
 )
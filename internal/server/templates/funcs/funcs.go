@@ -1,11 +1,56 @@
 package funcs
 
 import (
+	"fmt"
+	"mime"
+	"strings"
+
 	"github.com/marchuknikolay/rss-parser/internal/model"
 )
 
 const rssItemDateTimeLayout = "Mon, _2 Jan 2006 15:04:05"
 
+// FormatDate returns an empty string for unknown dates.
 func FormatDate(dt model.DateTime) string {
+	if dt.IsZero() {
+		return ""
+	}
+
 	return dt.Format(rssItemDateTimeLayout)
 }
+
+// FormatDuration formats seconds as H:MM:SS, or M:SS for less than an hour.
+// Unknown durations are formatted as an empty string.
+func FormatDuration(seconds int) string {
+	const (
+		secondsPerMinute = 60
+		secondsPerHour   = 60 * secondsPerMinute
+	)
+
+	if seconds <= 0 {
+		return ""
+	}
+
+	h, m, s := seconds/secondsPerHour, seconds%secondsPerHour/secondsPerMinute, seconds%secondsPerMinute
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+
+	return fmt.Sprintf("%d:%02d", m, s)
+}
+
+// MediaKind tells which player an enclosure of the given type needs: "audio",
+// "video", or "" for files that can only be downloaded.
+func MediaKind(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+
+	kind, _, _ := strings.Cut(mediaType, "/")
+	if kind == "audio" || kind == "video" {
+		return kind
+	}
+
+	return ""
+}
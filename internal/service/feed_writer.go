@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
+)
+
+// itemBatchSize is how many items of a channel are held before they are
+// looked up and saved together.
+const itemBatchSize = 100
+
+// feedWriter stores the channels of one document, which are passed to it one
+// after another, each followed by its items. Items are saved in batches, so
+// a document doesn't have to be held in memory whole to be stored.
+type feedWriter struct {
+	channelRepository repository.ChannelUpserter
+	itemRepository    repository.ItemUpserter
+
+	// stored are the channels previously imported from the same URL. A
+	// document may hold several channels, so they are matched by position.
+	stored []model.Channel
+	// channels is the number of channels written so far, first the first of
+	// them, whose polling hints plan the next refresh of the feed.
+	channels int
+	first    model.Channel
+
+	channelId int
+	// replaced is set when the last channel was imported before, so that
+	// the stored counterparts of its items are looked up.
+	replaced bool
+	matcher  *itemMatcher
+	batch    []model.Item
+	stats    ImportStats
+}
+
+// newFeedWriter locks url for the rest of the transaction before looking up
+// the channels imported from it, so that imports of the same URL running at
+// the same time store its channels once.
+func newFeedWriter(
+	ctx context.Context,
+	channelRepository repository.ChannelUpserter,
+	itemRepository repository.ItemUpserter,
+	url string,
+) (*feedWriter, error) {
+	if err := channelRepository.LockSourceUrl(ctx, url); err != nil {
+		return nil, err
+	}
+
+	stored, err := channelRepository.GetBySourceUrl(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return &feedWriter{
+		channelRepository: channelRepository,
+		itemRepository:    itemRepository,
+		stored:            stored,
+	}, nil
+}
+
+// channel saves the channel, finishing the previous one. Its items are
+// ignored, they are passed to item.
+func (w *feedWriter) channel(ctx context.Context, channel *model.Channel) error {
+	if err := w.flush(ctx); err != nil {
+		return err
+	}
+
+	w.replaced = w.channels < len(w.stored)
+
+	if w.replaced {
+		w.channelId = w.stored[w.channels].Id
+
+		if err := w.channelRepository.Replace(ctx, w.channelId, channel); err != nil {
+			return err
+		}
+	} else {
+		channelId, err := w.channelRepository.Save(ctx, channel)
+		if err != nil {
+			return err
+		}
+
+		w.channelId = channelId
+	}
+
+	if w.channels == 0 {
+		w.first = *channel
+	}
+
+	w.channels++
+	w.matcher = newItemMatcher(nil)
+	w.stats.ChannelIds = append(w.stats.ChannelIds, w.channelId)
+
+	return nil
+}
+
+// channelUpdate stores the complete metadata of the last channel, which the
+// document only had after some of its items.
+func (w *feedWriter) channelUpdate(ctx context.Context, channel *model.Channel) error {
+	if w.channels == 1 {
+		w.first = *channel
+	}
+
+	return w.channelRepository.Replace(ctx, w.channelId, channel)
+}
+
+// item adds an item of the last channel, saving the batch once it is full.
+func (w *feedWriter) item(ctx context.Context, item model.Item) error {
+	w.batch = append(w.batch, item)
+
+	if len(w.batch) < itemBatchSize {
+		return nil
+	}
+
+	return w.flush(ctx)
+}
+
+// flush saves the items that are held, comparing them with the stored items
+// they replace.
+func (w *feedWriter) flush(ctx context.Context) error {
+	if len(w.batch) == 0 {
+		return nil
+	}
+
+	if w.replaced {
+		existing, err := w.itemRepository.GetByKeys(ctx, w.channelId, w.matcher.lookupKeys(w.batch))
+		if err != nil {
+			return err
+		}
+
+		w.matcher.load(existing)
+	}
+
+	stats, err := saveItems(ctx, w.itemRepository, w.channelId, w.batch, w.matcher)
+	if err != nil {
+		return err
+	}
+
+	w.stats.add(stats)
+	w.batch = w.batch[:0]
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
+)
+
+// itemMatcher finds the stored counterpart of a freshly parsed item. Items are
+// identified by GUID, falling back to the link and then to a hash of their
+// content for feeds that provide neither.
+type itemMatcher struct {
+	byKey map[string]model.Item
+	seen  map[string]struct{}
+}
+
+func newItemMatcher(existing []model.Item) *itemMatcher {
+	m := &itemMatcher{seen: make(map[string]struct{})}
+	m.load(existing)
+
+	return m
+}
+
+// load replaces the stored items to match against, so that only those of the
+// batch being saved are held.
+func (m *itemMatcher) load(existing []model.Item) {
+	m.byKey = make(map[string]model.Item, len(existing))
+
+	for _, item := range existing {
+		key := itemKey(item)
+		if _, ok := m.byKey[key]; !ok {
+			m.byKey[key] = item
+		}
+	}
+}
+
+// lookupKeys returns what the stored counterparts of the items are looked up
+// by. Items matched before are left out.
+func (m *itemMatcher) lookupKeys(items []model.Item) repository.ItemKeys {
+	var keys repository.ItemKeys
+
+	for _, item := range items {
+		if _, ok := m.seen[itemKey(item)]; ok {
+			continue
+		}
+
+		switch {
+		case item.Guid.Value != "":
+			keys.Guids = append(keys.Guids, item.Guid.Value)
+		case item.Link != "":
+			keys.Links = append(keys.Links, item.Link)
+		default:
+			keys.Titles = append(keys.Titles, item.Title)
+		}
+	}
+
+	return keys
+}
+
+// match returns the stored item with the same identity. duplicate is true when
+// an item with the same identity was already matched earlier in the feed.
+func (m *itemMatcher) match(item model.Item) (existing model.Item, found, duplicate bool) {
+	key := itemKey(item)
+
+	if _, ok := m.seen[key]; ok {
+		return model.Item{}, false, true
+	}
+
+	m.seen[key] = struct{}{}
+	existing, found = m.byKey[key]
+
+	return existing, found, false
+}
+
+func itemKey(item model.Item) string {
+	if item.Guid.Value != "" {
+		return "guid:" + item.Guid.Value
+	}
+
+	if item.Link != "" {
+		return "link:" + item.Link
+	}
+
+	return "hash:" + contentHash(item)
+}
+
+func contentHash(item model.Item) string {
+	sum := sha256.Sum256([]byte(item.Title + "\x00" + item.Description))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// itemChanged compares the fields that come from the feed. Dates are compared
+// by their wall clock because that is what the database keeps.
+func itemChanged(stored, parsed model.Item) bool {
+	return stored.Title != parsed.Title ||
+		stored.Description != parsed.Description ||
+		stored.Content != parsed.Content ||
+		stored.Link != parsed.Link ||
+		stored.Author != parsed.Author ||
+		stored.Guid != parsed.Guid ||
+		stored.PubDate.String() != parsed.PubDate.String() ||
+		!slices.Equal(stored.Enclosures, parsed.Enclosures) ||
+		stored.Duration != parsed.Duration ||
+		stored.Episode != parsed.Episode ||
+		stored.Season != parsed.Season ||
+		stored.ImageUrl != parsed.ImageUrl ||
+		stored.Explicit != parsed.Explicit ||
+		!slices.Equal(stored.Attachments, parsed.Attachments) ||
+		stored.ThumbnailUrl != parsed.ThumbnailUrl ||
+		!sameCategories(stored.Categories, parsed.Categories)
+}
+
+// sameCategories ignores the order of categories, since the database doesn't
+// keep the order of the feed.
+func sameCategories(a, b []model.Category) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, c := range a {
+		if !slices.Contains(b, c) {
+			return false
+		}
+	}
+
+	return true
+}
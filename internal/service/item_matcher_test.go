@@ -0,0 +1,129 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
+	"github.com/marchuknikolay/rss-parser/internal/testutils"
+)
+
+func TestItemMatcher(t *testing.T) {
+	t.Run("ByGuid", func(t *testing.T) {
+		stored := testutils.CreateItemWithId(1)
+		parsed := stored
+		parsed.Id = 0
+		parsed.Link = "https://example.com/moved"
+
+		existing, found, duplicate := newItemMatcher([]model.Item{stored}).match(parsed)
+
+		require.True(t, found)
+		require.False(t, duplicate)
+		require.Equal(t, stored.Id, existing.Id)
+		require.True(t, itemChanged(existing, parsed))
+	})
+
+	t.Run("ByLink", func(t *testing.T) {
+		stored := model.Item{Id: 1, Title: "Title", Link: "https://example.com/a"}
+		parsed := model.Item{Title: "Title", Link: "https://example.com/a"}
+
+		existing, found, _ := newItemMatcher([]model.Item{stored}).match(parsed)
+
+		require.True(t, found)
+		require.Equal(t, 1, existing.Id)
+		require.False(t, itemChanged(existing, parsed))
+	})
+
+	t.Run("ContentChanged", func(t *testing.T) {
+		stored := testutils.CreateItemWithId(1)
+		parsed := stored
+		parsed.Content = "<p>Item 1 content, updated</p>"
+
+		require.True(t, itemChanged(stored, parsed))
+	})
+
+	t.Run("EnclosureChanged", func(t *testing.T) {
+		stored := testutils.CreateItemWithId(1)
+		parsed := stored
+		parsed.Enclosures = []model.Enclosure{{Url: "https://example.com/items/1-fixed.mp3", Type: "audio/mpeg"}}
+
+		require.True(t, itemChanged(stored, parsed))
+		require.False(t, itemChanged(stored, stored))
+	})
+
+	t.Run("AttachmentChanged", func(t *testing.T) {
+		stored := testutils.CreateItemWithId(1)
+		parsed := stored
+		parsed.Attachments = []model.Attachment{{Url: "https://example.com/items/1.jpg", Medium: "image"}}
+		parsed.ThumbnailUrl = "https://example.com/items/1.jpg"
+
+		require.True(t, itemChanged(stored, parsed))
+	})
+
+	t.Run("CategoriesChanged", func(t *testing.T) {
+		stored := testutils.CreateItemWithId(1)
+		stored.Categories = []model.Category{{Name: "go"}, {Name: "release"}}
+
+		reordered := stored
+		reordered.Categories = []model.Category{{Name: "release"}, {Name: "go"}}
+		require.False(t, itemChanged(stored, reordered))
+
+		parsed := stored
+		parsed.Categories = []model.Category{{Name: "go"}}
+		require.True(t, itemChanged(stored, parsed))
+	})
+
+	t.Run("ByContentHash", func(t *testing.T) {
+		stored := model.Item{Id: 1, Title: "Title", Description: "Description"}
+
+		matcher := newItemMatcher([]model.Item{stored})
+
+		_, found, _ := matcher.match(model.Item{Title: "Title", Description: "Description"})
+		require.True(t, found)
+
+		_, found, _ = matcher.match(model.Item{Title: "Title", Description: "Other"})
+		require.False(t, found)
+	})
+
+	t.Run("GuidDoesNotFallBackToLink", func(t *testing.T) {
+		stored := model.Item{Id: 1, Link: "https://example.com/a"}
+		parsed := model.Item{Link: "https://example.com/a", Guid: model.Guid{Value: "new", IsPermaLink: false}}
+
+		_, found, _ := newItemMatcher([]model.Item{stored}).match(parsed)
+
+		require.False(t, found)
+	})
+
+	t.Run("DuplicateInFeed", func(t *testing.T) {
+		item := testutils.CreateItemWithId(1)
+
+		matcher := newItemMatcher(nil)
+
+		_, found, duplicate := matcher.match(item)
+		require.False(t, found)
+		require.False(t, duplicate)
+
+		_, _, duplicate = matcher.match(item)
+		require.True(t, duplicate)
+	})
+
+	t.Run("LookupKeys", func(t *testing.T) {
+		m := newItemMatcher(nil)
+		m.match(model.Item{Guid: model.Guid{Value: "matched"}})
+
+		keys := m.lookupKeys([]model.Item{
+			{Guid: model.Guid{Value: "item-1"}, Link: "https://example.com/1"},
+			{Link: "https://example.com/2"},
+			{Title: "Item 3"},
+			{Guid: model.Guid{Value: "matched"}},
+		})
+
+		require.Equal(t, repository.ItemKeys{
+			Guids:  []string{"item-1"},
+			Links:  []string{"https://example.com/2"},
+			Titles: []string{"Item 3"},
+		}, keys)
+	})
+}
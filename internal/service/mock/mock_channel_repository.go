@@ -2,17 +2,23 @@ package mock
 
 import (
 	"context"
+	"time"
 
 	"github.com/marchuknikolay/rss-parser/internal/model"
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
 type MockChannelRepository struct {
-	SaveFunc    func(ctx context.Context, ch *model.Channel) (int, error)
-	GetAllFunc  func(ctx context.Context) ([]model.Channel, error)
-	GetByIdFunc func(ctx context.Context, id int) (model.Channel, error)
-	DeleteFunc  func(ctx context.Context, id int) error
-	UpdateFunc  func(ctx context.Context, id int, title, language, description string) (model.Channel, error)
+	SaveFunc           func(ctx context.Context, ch *model.Channel) (int, error)
+	GetAllFunc         func(ctx context.Context) ([]model.Channel, error)
+	GetByIdFunc        func(ctx context.Context, id int) (model.Channel, error)
+	GetBySourceUrlFunc func(ctx context.Context, sourceUrl string) ([]model.Channel, error)
+	LockSourceUrlFunc  func(ctx context.Context, sourceUrl string) error
+	DeleteFunc         func(ctx context.Context, id int) error
+	UpdateFunc         func(ctx context.Context, id int, title, language, description string) (model.Channel, error)
+	ReplaceFunc        func(ctx context.Context, id int, channel *model.Channel) error
+	MarkFetchedFunc    func(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error
+	MarkFailedFunc     func(ctx context.Context, sourceUrl string, failedAt, nextFetchAt time.Time, reason string) error
 }
 
 func (m *MockChannelRepository) Save(ctx context.Context, ch *model.Channel) (int, error) {
@@ -39,6 +45,22 @@ func (m *MockChannelRepository) GetById(ctx context.Context, id int) (model.Chan
 	return model.Channel{}, testutils.ErrNotImplemented
 }
 
+func (m *MockChannelRepository) GetBySourceUrl(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+	if m.GetBySourceUrlFunc != nil {
+		return m.GetBySourceUrlFunc(ctx, sourceUrl)
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
+func (m *MockChannelRepository) LockSourceUrl(ctx context.Context, sourceUrl string) error {
+	if m.LockSourceUrlFunc != nil {
+		return m.LockSourceUrlFunc(ctx, sourceUrl)
+	}
+
+	return testutils.ErrNotImplemented
+}
+
 func (m *MockChannelRepository) Delete(ctx context.Context, id int) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -58,3 +80,36 @@ func (m *MockChannelRepository) Update(
 
 	return model.Channel{}, testutils.ErrNotImplemented
 }
+
+func (m *MockChannelRepository) Replace(ctx context.Context, id int, channel *model.Channel) error {
+	if m.ReplaceFunc != nil {
+		return m.ReplaceFunc(ctx, id, channel)
+	}
+
+	return testutils.ErrNotImplemented
+}
+
+func (m *MockChannelRepository) MarkFetched(
+	ctx context.Context,
+	sourceUrl string,
+	fetchedAt, nextFetchAt time.Time,
+) error {
+	if m.MarkFetchedFunc != nil {
+		return m.MarkFetchedFunc(ctx, sourceUrl, fetchedAt, nextFetchAt)
+	}
+
+	return testutils.ErrNotImplemented
+}
+
+func (m *MockChannelRepository) MarkFailed(
+	ctx context.Context,
+	sourceUrl string,
+	failedAt, nextFetchAt time.Time,
+	reason string,
+) error {
+	if m.MarkFailedFunc != nil {
+		return m.MarkFailedFunc(ctx, sourceUrl, failedAt, nextFetchAt, reason)
+	}
+
+	return testutils.ErrNotImplemented
+}
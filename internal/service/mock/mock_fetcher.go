@@ -1,19 +1,42 @@
 package mock
 
 import (
+	"bytes"
 	"context"
+	"io"
 
+	"github.com/marchuknikolay/rss-parser/internal/fetcher"
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
+// MockFetcher answers Open from FetchFunc unless OpenFunc is set, so that most
+// tests only have to describe the response once.
 type MockFetcher struct {
-	FetchFunc func(ctx context.Context, url string) ([]byte, error)
+	FetchFunc func(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Response, error)
+	OpenFunc  func(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Stream, error)
 }
 
-func (m MockFetcher) Fetch(ctx context.Context, url string) ([]byte, error) {
+func (m MockFetcher) Fetch(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Response, error) {
 	if m.FetchFunc != nil {
-		return m.FetchFunc(ctx, url)
+		return m.FetchFunc(ctx, url, validators)
 	}
 
-	return nil, testutils.ErrNotImplemented
+	return fetcher.Response{}, testutils.ErrNotImplemented
+}
+
+func (m MockFetcher) Open(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Stream, error) {
+	if m.OpenFunc != nil {
+		return m.OpenFunc(ctx, url, validators)
+	}
+
+	resp, err := m.Fetch(ctx, url, validators)
+	if err != nil || resp.NotModified {
+		return fetcher.Stream{Validators: resp.Validators, NotModified: resp.NotModified}, err
+	}
+
+	return fetcher.Stream{
+		Body:            io.NopCloser(bytes.NewReader(resp.Body)),
+		ContentEncoding: resp.ContentEncoding,
+		Validators:      resp.Validators,
+	}, nil
 }
@@ -5,21 +5,31 @@ import (
 	"time"
 
 	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
 type MockItemRepository struct {
-	SaveFunc           func(ctx context.Context, item model.Item, channelId int) error
+	SaveAllFunc        func(ctx context.Context, items []model.Item, channelId int) error
 	GetAllFunc         func(ctx context.Context) ([]model.Item, error)
 	GetByChannelIdFunc func(ctx context.Context, channelId int) ([]model.Item, error)
+	GetByKeysFunc      func(ctx context.Context, channelId int, keys repository.ItemKeys) ([]model.Item, error)
 	GetByIdFunc        func(ctx context.Context, id int) (model.Item, error)
+	GetByCategoryFunc  func(ctx context.Context, category string) ([]model.Item, error)
+	GetCategoriesFunc  func(ctx context.Context) ([]model.CategoryCount, error)
 	DeleteFunc         func(ctx context.Context, id int) error
-	UpdateFunc         func(ctx context.Context, id int, title, description string, pubDate time.Time) (model.Item, error)
+	UpdateFunc         func(
+		ctx context.Context,
+		id int,
+		title, description, link, author string,
+		pubDate time.Time,
+	) (model.Item, error)
+	ReplaceFunc func(ctx context.Context, id int, item model.Item) error
 }
 
-func (m *MockItemRepository) Save(ctx context.Context, item model.Item, channelId int) error {
-	if m.SaveFunc != nil {
-		return m.SaveFunc(ctx, item, channelId)
+func (m *MockItemRepository) SaveAll(ctx context.Context, items []model.Item, channelId int) error {
+	if m.SaveAllFunc != nil {
+		return m.SaveAllFunc(ctx, items, channelId)
 	}
 
 	return testutils.ErrNotImplemented
@@ -41,6 +51,18 @@ func (m *MockItemRepository) GetByChannelId(ctx context.Context, channelId int)
 	return nil, testutils.ErrNotImplemented
 }
 
+func (m *MockItemRepository) GetByKeys(
+	ctx context.Context,
+	channelId int,
+	keys repository.ItemKeys,
+) ([]model.Item, error) {
+	if m.GetByKeysFunc != nil {
+		return m.GetByKeysFunc(ctx, channelId, keys)
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
 func (m *MockItemRepository) GetById(ctx context.Context, id int) (model.Item, error) {
 	if m.GetByIdFunc != nil {
 		return m.GetByIdFunc(ctx, id)
@@ -49,6 +71,22 @@ func (m *MockItemRepository) GetById(ctx context.Context, id int) (model.Item, e
 	return model.Item{}, testutils.ErrNotImplemented
 }
 
+func (m *MockItemRepository) GetByCategory(ctx context.Context, category string) ([]model.Item, error) {
+	if m.GetByCategoryFunc != nil {
+		return m.GetByCategoryFunc(ctx, category)
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
+func (m *MockItemRepository) GetCategories(ctx context.Context) ([]model.CategoryCount, error) {
+	if m.GetCategoriesFunc != nil {
+		return m.GetCategoriesFunc(ctx)
+	}
+
+	return nil, testutils.ErrNotImplemented
+}
+
 func (m *MockItemRepository) Delete(ctx context.Context, id int) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -60,12 +98,20 @@ func (m *MockItemRepository) Delete(ctx context.Context, id int) error {
 func (m *MockItemRepository) Update(
 	ctx context.Context,
 	id int,
-	title, description string,
+	title, description, link, author string,
 	pubDate time.Time,
 ) (model.Item, error) {
 	if m.UpdateFunc != nil {
-		return m.UpdateFunc(ctx, id, title, description, pubDate)
+		return m.UpdateFunc(ctx, id, title, description, link, author, pubDate)
 	}
 
 	return model.Item{}, testutils.ErrNotImplemented
 }
+
+func (m *MockItemRepository) Replace(ctx context.Context, id int, item model.Item) error {
+	if m.ReplaceFunc != nil {
+		return m.ReplaceFunc(ctx, id, item)
+	}
+
+	return testutils.ErrNotImplemented
+}
@@ -1,12 +1,16 @@
 package mock
 
 import (
+	"io"
+
 	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
 	"github.com/marchuknikolay/rss-parser/internal/testutils"
 )
 
 type MockParser struct {
-	ParseFunc func(bs []byte) (model.Rss, error)
+	ParseFunc  func(bs []byte) (model.Rss, error)
+	StreamFunc func(r io.Reader, h parser.StreamHandler) error
 }
 
 func (m MockParser) Parse(bs []byte) (model.Rss, error) {
@@ -16,3 +20,11 @@ func (m MockParser) Parse(bs []byte) (model.Rss, error) {
 
 	return model.Rss{}, testutils.ErrNotImplemented
 }
+
+func (m MockParser) Stream(r io.Reader, h parser.StreamHandler) error {
+	if m.StreamFunc != nil {
+		return m.StreamFunc(r, h)
+	}
+
+	return testutils.ErrNotImplemented
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"slices"
+	"time"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+// maxHintedInterval caps the publisher hints, so a feed claiming to update
+// yearly is still checked every week.
+const maxHintedInterval = 7 * 24 * time.Hour
+
+// nextFetch plans the refresh of ch after a successful fetch at now. The
+// global interval is the minimum; <ttl> and sy:updatePeriod/updateFrequency
+// can only make polling less frequent. The result is then moved out of the
+// hours and days listed in <skipHours> and <skipDays>, which are in GMT.
+func nextFetch(ch *model.Channel, now time.Time, interval time.Duration) time.Time {
+	wait := max(interval, min(hintedInterval(ch), maxHintedInterval))
+
+	return skipForbidden(ch, now.Add(wait))
+}
+
+func hintedInterval(ch *model.Channel) time.Duration {
+	ttl := time.Duration(ch.Ttl) * time.Minute
+
+	return max(ttl, updateInterval(ch.UpdatePeriod, ch.UpdateFrequency))
+}
+
+// updateInterval converts the syndication module hint, e.g. "daily" with a
+// frequency of 2 means every 12 hours. The frequency defaults to 1.
+func updateInterval(period string, frequency int) time.Duration {
+	const day = 24 * time.Hour
+
+	var d time.Duration
+
+	switch period {
+	case "hourly":
+		d = time.Hour
+	case "daily":
+		d = day
+	case "weekly":
+		d = 7 * day
+	case "monthly":
+		d = 30 * day
+	case "yearly":
+		d = 365 * day
+	default:
+		return 0
+	}
+
+	if frequency > 1 {
+		d /= time.Duration(frequency)
+	}
+
+	return d
+}
+
+func skipForbidden(ch *model.Channel, t time.Time) time.Time {
+	const hoursPerWeek = 7 * 24
+
+	if len(ch.SkipHours) == 0 && len(ch.SkipDays) == 0 {
+		return t
+	}
+
+	candidate := t
+
+	// A week covers every combination of hour and day, so if no slot is
+	// allowed within it the hints are contradictory and ignored.
+	for range hoursPerWeek {
+		if !isSkipped(ch, candidate) {
+			return candidate
+		}
+
+		candidate = candidate.UTC().Truncate(time.Hour).Add(time.Hour)
+	}
+
+	return t
+}
+
+func isSkipped(ch *model.Channel, t time.Time) bool {
+	t = t.UTC()
+
+	return slices.Contains(ch.SkipHours, t.Hour()) || slices.Contains(ch.SkipDays, t.Weekday().String())
+}
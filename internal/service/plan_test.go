@@ -0,0 +1,81 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/model"
+)
+
+func TestNextFetch(t *testing.T) {
+	// Monday
+	now := time.Date(2025, 7, 28, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		channel  model.Channel
+		interval time.Duration
+		expected time.Time
+	}{
+		{
+			name:     "GlobalInterval",
+			channel:  model.Channel{},
+			interval: 30 * time.Minute,
+			expected: now.Add(30 * time.Minute),
+		},
+		{
+			name:     "TtlLongerThanInterval",
+			channel:  model.Channel{Ttl: 120},
+			interval: 30 * time.Minute,
+			expected: now.Add(2 * time.Hour),
+		},
+		{
+			name:     "TtlShorterThanInterval",
+			channel:  model.Channel{Ttl: 5},
+			interval: 30 * time.Minute,
+			expected: now.Add(30 * time.Minute),
+		},
+		{
+			name:     "UpdatePeriodWithFrequency",
+			channel:  model.Channel{UpdatePeriod: "daily", UpdateFrequency: 4},
+			interval: 30 * time.Minute,
+			expected: now.Add(6 * time.Hour),
+		},
+		{
+			name:     "UpdatePeriodCapped",
+			channel:  model.Channel{UpdatePeriod: "yearly"},
+			interval: 30 * time.Minute,
+			expected: now.Add(maxHintedInterval),
+		},
+		{
+			name:     "SkipHours",
+			channel:  model.Channel{SkipHours: []int{11, 12}},
+			interval: 30 * time.Minute,
+			expected: time.Date(2025, 7, 28, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "SkipDays",
+			channel:  model.Channel{Ttl: 24 * 60, SkipDays: []string{"Tuesday"}},
+			interval: 30 * time.Minute,
+			expected: time.Date(2025, 7, 30, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "EverythingSkipped",
+			channel: model.Channel{
+				SkipDays: []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+			},
+			interval: 30 * time.Minute,
+			expected: now.Add(30 * time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := nextFetch(&tt.channel, now, tt.interval)
+
+			require.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -1,24 +1,36 @@
 package service
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"runtime"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
+	"github.com/marchuknikolay/rss-parser/internal/discovery"
+	"github.com/marchuknikolay/rss-parser/internal/fetcher"
 	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
 	"github.com/marchuknikolay/rss-parser/internal/repository"
+	"github.com/marchuknikolay/rss-parser/internal/resolver"
 	"github.com/marchuknikolay/rss-parser/internal/storage"
+	"github.com/marchuknikolay/rss-parser/internal/validator"
 )
 
 type FetcherInterface interface {
-	Fetch(ctx context.Context, url string) ([]byte, error)
+	Fetch(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Response, error)
+	Open(ctx context.Context, url string, validators fetcher.Validators) (fetcher.Stream, error)
 }
 
 type ParserInterface interface {
 	Parse(bs []byte) (model.Rss, error)
+	Stream(r io.Reader, h parser.StreamHandler) error
 }
 
 type ChannelRepositoryFactoryInterface interface {
@@ -42,6 +54,31 @@ type Service struct {
 	// Repositories for simple calls
 	channelRepository repository.ChannelRepositoryInterface
 	itemRepository    repository.ItemRepositoryInterface
+
+	refreshInterval time.Duration
+	maxDecodedSize  int64
+}
+
+// defaultMaxDecodedSize is used unless WithMaxDecodedSize says otherwise.
+const defaultMaxDecodedSize = 64 << 20
+
+type Option func(*Service)
+
+// WithRefreshInterval sets the global refresh interval of the scheduler. Each
+// import plans the next refresh of the channels it stores from it and the
+// polling hints of the feed, see nextFetch.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(s *Service) {
+		s.refreshInterval = interval
+	}
+}
+
+// WithMaxDecodedSize limits the size of a fetched document once its
+// Content-Encoding is undone, see decoder.Decompress.
+func WithMaxDecodedSize(size int64) Option {
+	return func(s *Service) {
+		s.maxDecodedSize = size
+	}
 }
 
 func New(
@@ -50,8 +87,9 @@ func New(
 	st storage.Interface,
 	channelRepoFactory ChannelRepositoryFactoryInterface,
 	itemRepoFactory ItemRepositoryFactoryInterface,
+	opts ...Option,
 ) *Service {
-	return &Service{
+	s := &Service{
 		fetcher:                  f,
 		parser:                   p,
 		storage:                  st,
@@ -59,14 +97,230 @@ func New(
 		itemRepositoryFactory:    itemRepoFactory,
 		channelRepository:        channelRepoFactory.New(st),
 		itemRepository:           itemRepoFactory.New(st),
+		maxDecodedSize:           defaultMaxDecodedSize,
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-func (s *Service) ImportFeeds(ctx context.Context, urls []string) error {
+// ErrFeedNotFound is returned when an HTML page neither links to a feed nor
+// has one at a well-known path.
+var ErrFeedNotFound = errors.New("no feed found")
+
+// ImportError collects the failures of ImportFeeds, one per URL. The original
+// errors can be inspected with errors.Is and errors.As.
+type ImportError struct {
+	Errs []error
+}
+
+func (e *ImportError) Error() string {
+	messages := make([]string, 0, len(e.Errs))
+	for _, err := range e.Errs {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("failed to import %v feeds: - %v", len(e.Errs), strings.Join(messages, "; - "))
+}
+
+func (e *ImportError) Unwrap() []error {
+	return e.Errs
+}
+
+// ErrorCategory tells which step of an import failed.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryNetwork is a failed connection or an interrupted download.
+	ErrorCategoryNetwork ErrorCategory = "network"
+	// ErrorCategoryHttpStatus is a response other than 200 OK or 304 Not
+	// Modified, see fetcher.StatusError.
+	ErrorCategoryHttpStatus ErrorCategory = "http_status"
+	// ErrorCategoryRejected is a response that can't be a feed, see
+	// fetcher.RejectedError.
+	ErrorCategoryRejected ErrorCategory = "rejected"
+	// ErrorCategoryNotFound is an HTML page without a feed, see
+	// ErrFeedNotFound.
+	ErrorCategoryNotFound ErrorCategory = "not_found"
+	// ErrorCategoryParse is a document that can't be decoded.
+	ErrorCategoryParse ErrorCategory = "parse"
+	// ErrorCategoryDatabase is a failure to read or store the feed.
+	ErrorCategoryDatabase ErrorCategory = "database"
+)
+
+// FeedError is the failure of importing one feed, with the category of the
+// step that failed. Err can be inspected with errors.Is and errors.As.
+type FeedError struct {
+	Category ErrorCategory
+	Err      error
+}
+
+func (e *FeedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FeedError) Unwrap() error {
+	return e.Err
+}
+
+// categorize wraps err in a *FeedError, unless it already is one, since the
+// innermost step knows best what failed.
+func categorize(category ErrorCategory, err error) error {
+	var feedErr *FeedError
+	if err == nil || errors.As(err, &feedErr) {
+		return err
+	}
+
+	return &FeedError{Category: category, Err: err}
+}
+
+func fetchErrorCategory(err error) ErrorCategory {
+	var (
+		statusErr *fetcher.StatusError
+		rejected  *fetcher.RejectedError
+	)
+
+	switch {
+	case errors.As(err, &statusErr):
+		return ErrorCategoryHttpStatus
+	case errors.As(err, &rejected):
+		return ErrorCategoryRejected
+	default:
+		return ErrorCategoryNetwork
+	}
+}
+
+// ImportStats counts what happened to the items of imported feeds.
+type ImportStats struct {
+	Inserted  int
+	Updated   int
+	Unchanged int
+	// ChannelIds are the channels created or updated by the import.
+	ChannelIds []int
+	// NotModified is set when the server reported the feed unchanged, so
+	// nothing was stored.
+	NotModified bool
+	// Discovered lists the page URLs that were imported through the feed
+	// they link to.
+	Discovered []Discovery
+}
+
+// Discovery tells which feed was imported for a page URL.
+type Discovery struct {
+	PageUrl string
+	FeedUrl string
+}
+
+func (st *ImportStats) add(other ImportStats) {
+	st.Inserted += other.Inserted
+	st.Updated += other.Updated
+	st.Unchanged += other.Unchanged
+	st.ChannelIds = append(st.ChannelIds, other.ChannelIds...)
+	st.Discovered = append(st.Discovered, other.Discovered...)
+}
+
+// Subscription is a feed to import, optionally filed under a folder.
+type Subscription struct {
+	Url    string
+	Folder string
+}
+
+// FeedStatus is the outcome of importing one subscription.
+type FeedStatus string
+
+const (
+	FeedStatusImported    FeedStatus = "imported"
+	FeedStatusNotModified FeedStatus = "not_modified"
+	FeedStatusFailed      FeedStatus = "failed"
+)
+
+// FeedResult is the outcome of importing one subscription. Error and
+// ErrorCategory describe Err for clients that can't inspect it, DurationMs
+// describes Duration, which JSON would give in nanoseconds.
+type FeedResult struct {
+	Subscription
+	Status        FeedStatus
+	Stats         ImportStats
+	Duration      time.Duration `json:"-"`
+	DurationMs    int64
+	Err           error `json:"-"`
+	Error         string
+	ErrorCategory ErrorCategory
+}
+
+func newFeedResult(sub Subscription, stats ImportStats, duration time.Duration, err error) FeedResult {
+	duration = duration.Round(time.Millisecond)
+
+	result := FeedResult{
+		Subscription: sub,
+		Status:       FeedStatusImported,
+		Stats:        stats,
+		Duration:     duration,
+		DurationMs:   duration.Milliseconds(),
+	}
+
+	if stats.NotModified {
+		result.Status = FeedStatusNotModified
+	}
+
+	if err != nil {
+		result.Status = FeedStatusFailed
+		result.Err = fmt.Errorf("URL: %v, Error: %w", sub.Url, err)
+		result.Error = err.Error()
+		result.ErrorCategory = ErrorCategoryNetwork
+
+		var feedErr *FeedError
+		if errors.As(err, &feedErr) {
+			result.ErrorCategory = feedErr.Category
+		}
+	}
+
+	return result
+}
+
+// ImportReport lists the result of every imported subscription in the order
+// they were given, together with the stats of the successful ones.
+type ImportReport struct {
+	Feeds []FeedResult
+	Total ImportStats
+}
+
+// ImportFeeds imports every subscription concurrently. A URL given more than
+// once is imported once, into the folder of its first occurrence. The report
+// is complete even when some imports failed, the failures are also returned
+// as an *ImportError. The error of every failed import is a *FeedError.
+func (s *Service) ImportFeeds(ctx context.Context, subscriptions []Subscription) (ImportReport, error) {
 	maxWorkers := runtime.GOMAXPROCS(0)
 
-	dataChan := make(chan string)
-	errorsChan := make(chan string)
+	type job struct {
+		index        int
+		subscription Subscription
+	}
+
+	type result struct {
+		index int
+		FeedResult
+	}
+
+	// The same URL imported twice in parallel would race to create the
+	// channel, so duplicates are only imported once.
+	jobs := make([]job, 0, len(subscriptions))
+	seen := make(map[string]struct{}, len(subscriptions))
+
+	for _, sub := range subscriptions {
+		if _, ok := seen[sub.Url]; ok {
+			continue
+		}
+
+		seen[sub.Url] = struct{}{}
+		jobs = append(jobs, job{index: len(jobs), subscription: sub})
+	}
+
+	dataChan := make(chan job)
+	resultsChan := make(chan result)
 
 	var wg sync.WaitGroup
 	wg.Add(maxWorkers)
@@ -75,17 +329,21 @@ func (s *Service) ImportFeeds(ctx context.Context, urls []string) error {
 		go func() {
 			defer wg.Done()
 
-			for url := range dataChan {
-				if err := s.ImportFeed(ctx, url); err != nil {
-					errorsChan <- fmt.Sprintf("URL: %v, Error: %v", url, err)
+			for j := range dataChan {
+				start := time.Now()
+				stats, err := s.importFeed(ctx, j.subscription)
+
+				resultsChan <- result{
+					index:      j.index,
+					FeedResult: newFeedResult(j.subscription, stats, time.Since(start), err),
 				}
 			}
 		}()
 	}
 
 	go func() {
-		for _, url := range urls {
-			dataChan <- url
+		for _, j := range jobs {
+			dataChan <- j
 		}
 
 		close(dataChan)
@@ -93,33 +351,372 @@ func (s *Service) ImportFeeds(ctx context.Context, urls []string) error {
 
 	go func() {
 		wg.Wait()
-		close(errorsChan)
+		close(resultsChan)
 	}()
 
-	errorsStr := make([]string, 0, len(urls))
-	for err := range errorsChan {
-		errorsStr = append(errorsStr, err)
+	var (
+		report = ImportReport{Feeds: make([]FeedResult, len(jobs))}
+		errs   []error
+	)
+
+	for r := range resultsChan {
+		report.Feeds[r.index] = r.FeedResult
+
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+
+			continue
+		}
+
+		report.Total.add(r.Stats)
 	}
 
-	if errorsNum := len(errorsStr); errorsNum > 0 {
-		return fmt.Errorf("failed to import %v feeds: - %v", errorsNum, strings.Join(errorsStr, "; - "))
+	if len(errs) > 0 {
+		return report, &ImportError{Errs: errs}
 	}
 
-	return nil
+	return report, nil
 }
 
-func (s *Service) ImportFeed(ctx context.Context, url string) error {
-	bs, err := s.fetcher.Fetch(ctx, url)
+// ImportFeed fetches the feed at url and stores it. Importing the same URL
+// again updates the previously stored channels and items instead of
+// duplicating them, and is a no-op if the server reports the feed unchanged.
+// If url points to an HTML page, the feed it links to is imported instead.
+// Failures are returned as a *FeedError.
+func (s *Service) ImportFeed(ctx context.Context, url string) (ImportStats, error) {
+	return s.importFeed(ctx, Subscription{Url: url})
+}
+
+func (s *Service) importFeed(ctx context.Context, sub Subscription) (ImportStats, error) {
+	url := sub.Url
+
+	stored, err := s.channelRepository.GetBySourceUrl(ctx, url)
 	if err != nil {
-		return err
+		return ImportStats{}, categorize(ErrorCategoryDatabase, err)
 	}
 
-	rss, err := s.parser.Parse(bs)
+	doc, err := s.openDocument(ctx, url, storedValidators(stored))
 	if err != nil {
+		return ImportStats{}, err
+	}
+
+	if doc.notModified {
+		stats, err := s.notModified(ctx, url, stored)
+
+		return stats, categorize(ErrorCategoryDatabase, err)
+	}
+
+	stats, ok, err := s.importOpened(ctx, sub, doc)
+	doc.close(url)
+
+	if err != nil || ok {
+		return stats, err
+	}
+
+	return s.importWhole(ctx, sub)
+}
+
+// importOpened imports a document while it is being downloaded. It reports
+// false, having stored nothing, when the document has to be read at once
+// instead, e.g. because it is malformed and has to be repaired by Parse.
+func (s *Service) importOpened(ctx context.Context, sub Subscription, doc document) (ImportStats, bool, error) {
+	if !discovery.IsHtml(doc.head()) {
+		stats, ok, err := s.streamFeed(ctx, sub, doc)
+
+		return stats, ok, categorize(ErrorCategoryDatabase, err)
+	}
+
+	body, err := io.ReadAll(doc.reader)
+	if err != nil {
+		log.Printf("Reading page %v at once: %v", sub.Url, err)
+
+		return ImportStats{}, false, nil
+	}
+
+	stats, err := s.importDiscovered(ctx, sub, body)
+
+	return stats, true, categorize(ErrorCategoryDatabase, err)
+}
+
+// importWhole fetches the document at sub.Url again and imports it from
+// memory. Network errors are retried this way, unlike while streaming.
+func (s *Service) importWhole(ctx context.Context, sub Subscription) (ImportStats, error) {
+	doc, err := s.fetchDocument(ctx, sub.Url, fetcher.Validators{})
+	if err != nil {
+		return ImportStats{}, err
+	}
+
+	if discovery.IsHtml(doc.body) {
+		stats, err := s.importDiscovered(ctx, sub, doc.body)
+
+		return stats, categorize(ErrorCategoryDatabase, err)
+	}
+
+	rss, err := s.parser.Parse(doc.body)
+	if err != nil {
+		return ImportStats{}, categorize(ErrorCategoryParse, err)
+	}
+
+	stats, err := s.saveFeed(ctx, sub, doc, rss)
+
+	return stats, categorize(ErrorCategoryDatabase, err)
+}
+
+// ValidateFeed fetches and parses the feed at url like ImportFeed does, but
+// reports what is wrong with it instead of storing it. Only a failed download
+// is returned as an error, problems with the document are in the report.
+func (s *Service) ValidateFeed(ctx context.Context, url string) (validator.Report, error) {
+	return ValidateFeed(ctx, s.fetcher, s.parser, s.maxDecodedSize, url)
+}
+
+// ValidateFeed is Service.ValidateFeed for commands without a database. A
+// maxDecodedSize of 0 or less disables the limit, see decoder.Decompress.
+func ValidateFeed(
+	ctx context.Context,
+	f FetcherInterface,
+	p ParserInterface,
+	maxDecodedSize int64,
+	url string,
+) (validator.Report, error) {
+	doc, err := downloadDocument(ctx, f, maxDecodedSize, url, fetcher.Validators{})
+	if err != nil {
+		return validator.Report{}, err
+	}
+
+	return validator.Validate(url, doc.body, p), nil
+}
+
+// importDiscovered imports the first of the candidate feeds of a page that
+// can be fetched and parsed. Sites commonly announce the same posts in
+// several formats, so importing all of them would duplicate the items.
+func (s *Service) importDiscovered(ctx context.Context, page Subscription, body []byte) (ImportStats, error) {
+	pageUrl := page.Url
+
+	feedUrls, err := discovery.FeedUrls(body, pageUrl)
+	if err != nil {
+		return ImportStats{}, categorize(ErrorCategoryParse, err)
+	}
+
+	for _, feedUrl := range feedUrls {
+		if feedUrl == pageUrl {
+			continue
+		}
+
+		stats, ok, err := s.importCandidate(ctx, Subscription{Url: feedUrl, Folder: page.Folder})
+		if err != nil {
+			return ImportStats{}, err
+		}
+
+		if ok {
+			stats.Discovered = append(stats.Discovered, Discovery{PageUrl: pageUrl, FeedUrl: feedUrl})
+
+			return stats, nil
+		}
+	}
+
+	return ImportStats{}, categorize(ErrorCategoryNotFound, fmt.Errorf("%w at %v", ErrFeedNotFound, pageUrl))
+}
+
+// importCandidate imports the candidate URL if it holds a feed. A candidate that fails
+// to download or parse is skipped, only storage errors are returned.
+func (s *Service) importCandidate(ctx context.Context, candidate Subscription) (ImportStats, bool, error) {
+	feedUrl := candidate.Url
+
+	stored, err := s.channelRepository.GetBySourceUrl(ctx, feedUrl)
+	if err != nil {
+		return ImportStats{}, false, err
+	}
+
+	doc, err := s.fetchDocument(ctx, feedUrl, storedValidators(stored))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ImportStats{}, false, err
+		}
+
+		log.Printf("Skipping feed candidate %v: %v", feedUrl, err)
+
+		return ImportStats{}, false, nil
+	}
+
+	if doc.notModified {
+		stats, err := s.notModified(ctx, feedUrl, stored)
+
+		return stats, err == nil, err
+	}
+
+	if discovery.IsHtml(doc.body) {
+		return ImportStats{}, false, nil
+	}
+
+	rss, err := s.parser.Parse(doc.body)
+	if err != nil {
+		log.Printf("Skipping feed candidate %v: %v", feedUrl, err)
+
+		return ImportStats{}, false, nil
+	}
+
+	stats, err := s.saveFeed(ctx, candidate, doc, rss)
+
+	return stats, err == nil, err
+}
+
+// sniffSize is how much of an opened document is looked at to tell an HTML
+// page from a feed.
+const sniffSize = 4 << 10
+
+// document is a fetched and decompressed response. Its content is in body,
+// or in reader for a document that is opened rather than read at once.
+type document struct {
+	body        []byte
+	reader      *bufio.Reader
+	closer      io.Closer
+	validators  fetcher.Validators
+	notModified bool
+}
+
+// openDocument returns failures as a *FeedError. The document has to be
+// closed, unless it is notModified.
+func (s *Service) openDocument(ctx context.Context, url string, validators fetcher.Validators) (document, error) {
+	stream, err := s.fetcher.Open(ctx, url, validators)
+	if err != nil {
+		return document{}, categorize(fetchErrorCategory(err), err)
+	}
+
+	if stream.NotModified {
+		return document{notModified: true}, nil
+	}
+
+	doc := document{closer: stream.Body, validators: stream.Validators}
+
+	r, err := decoder.NewReader(stream.Body, stream.ContentEncoding, s.maxDecodedSize)
+	if err != nil {
+		doc.close(url)
+
+		return document{}, categorize(ErrorCategoryParse, err)
+	}
+
+	doc.reader = bufio.NewReaderSize(r, sniffSize)
+
+	return doc, nil
+}
+
+// head returns the start of an opened document without consuming it.
+func (d document) head() []byte {
+	// A failure is left for the next read to report.
+	bs, _ := d.reader.Peek(sniffSize) //nolint:errcheck
+
+	return bs
+}
+
+func (d document) close(url string) {
+	if err := d.closer.Close(); err != nil {
+		log.Printf("Failed to close the response body of %v: %v", url, err)
+	}
+}
+
+// fetchDocument returns failures as a *FeedError.
+func (s *Service) fetchDocument(ctx context.Context, url string, validators fetcher.Validators) (document, error) {
+	return downloadDocument(ctx, s.fetcher, s.maxDecodedSize, url, validators)
+}
+
+func downloadDocument(
+	ctx context.Context,
+	f FetcherInterface,
+	maxDecodedSize int64,
+	url string,
+	validators fetcher.Validators,
+) (document, error) {
+	resp, err := f.Fetch(ctx, url, validators)
+	if err != nil {
+		return document{}, categorize(fetchErrorCategory(err), err)
+	}
+
+	if resp.NotModified {
+		return document{notModified: true}, nil
+	}
+
+	body, err := decoder.Decompress(resp.Body, resp.ContentEncoding, maxDecodedSize)
+	if err != nil {
+		return document{}, categorize(ErrorCategoryParse, err)
+	}
+
+	return document{body: body, validators: resp.Validators}, nil
+}
+
+func (s *Service) saveFeed(ctx context.Context, sub Subscription, doc document, rss model.Rss) (ImportStats, error) {
+	for _, warning := range rss.Warnings {
+		log.Printf("Warning: feed %v: %v", sub.Url, warning)
+	}
+
+	for i := range rss.Channels {
+		prepareChannel(&rss.Channels[i], sub, doc)
+	}
+
+	return s.saveChannels(ctx, sub.Url, rss.Channels)
+}
+
+// streamFeed saves the document while it is being decoded, so that its items
+// are never all held in memory. It reports false, having stored nothing, when
+// the document can't be decoded this way, e.g. because it is malformed and
+// has to be repaired by Parse first.
+func (s *Service) streamFeed(ctx context.Context, sub Subscription, doc document) (ImportStats, bool, error) {
+	var decodeErr error
+
+	stats, err := s.writeFeed(ctx, sub.Url, func(w *feedWriter) error {
+		var (
+			channel  model.Channel
+			writeErr error
+		)
+
+		err := s.parser.Stream(doc.reader, parser.StreamHandler{
+			Channel: func(c model.Channel) error {
+				channel = c
+				prepareChannel(&channel, sub, doc)
+				writeErr = w.channel(ctx, &channel)
+
+				return writeErr
+			},
+			Item: func(item model.Item) error {
+				resolver.Item(&item, &channel, sub.Url)
+				writeErr = w.item(ctx, item)
+
+				return writeErr
+			},
+			ChannelUpdate: func(c model.Channel) error {
+				channel = c
+				prepareChannel(&channel, sub, doc)
+				writeErr = w.channelUpdate(ctx, &channel)
+
+				return writeErr
+			},
+			Warning: func(warning string) {
+				log.Printf("Warning: feed %v: %v", sub.Url, warning)
+			},
+		})
+		if err != nil && writeErr == nil {
+			decodeErr = err
+		}
+
 		return err
+	})
+	if decodeErr != nil {
+		log.Printf("Decoding feed %v at once: %v", sub.Url, decodeErr)
+
+		return ImportStats{}, false, nil
 	}
 
-	return s.saveChannels(ctx, rss.Channels)
+	return stats, err == nil, err
+}
+
+// prepareChannel fills in where the channel was fetched from and makes its
+// URLs absolute.
+func prepareChannel(channel *model.Channel, sub Subscription, doc document) {
+	channel.SourceUrl = sub.Url
+	channel.Folder = sub.Folder
+	channel.Etag = doc.validators.Etag
+	channel.LastModified = doc.validators.LastModified
+
+	resolver.Channel(channel, sub.Url)
 }
 
 func (s *Service) GetChannels(ctx context.Context) ([]model.Channel, error) {
@@ -161,6 +758,16 @@ func (s *Service) UpdateChannel(
 	return s.channelRepository.Update(ctx, id, title, language, description)
 }
 
+// RecordFetchFailure stores why refreshing the channels imported from url failed.
+func (s *Service) RecordFetchFailure(
+	ctx context.Context,
+	url string,
+	failedAt, nextFetchAt time.Time,
+	reason string,
+) error {
+	return s.channelRepository.MarkFailed(ctx, url, failedAt, nextFetchAt, reason)
+}
+
 func (s *Service) GetItems(ctx context.Context) ([]model.Item, error) {
 	return s.itemRepository.GetAll(ctx)
 }
@@ -169,6 +776,18 @@ func (s *Service) GetItemsByChannelId(ctx context.Context, channelId int) ([]mod
 	return s.itemRepository.GetByChannelId(ctx, channelId)
 }
 
+// GetItemsByCategory returns the items filed under the category, matched
+// case-insensitively.
+func (s *Service) GetItemsByCategory(ctx context.Context, category string) ([]model.Item, error) {
+	return s.itemRepository.GetByCategory(ctx, strings.TrimSpace(category))
+}
+
+// GetCategories returns the category names in use with the number of items
+// filed under each.
+func (s *Service) GetCategories(ctx context.Context) ([]model.CategoryCount, error) {
+	return s.itemRepository.GetCategories(ctx)
+}
+
 func (s *Service) GetItemById(ctx context.Context, itemId int) (model.Item, error) {
 	return s.itemRepository.GetById(ctx, itemId)
 }
@@ -180,28 +799,56 @@ func (s *Service) DeleteItem(ctx context.Context, itemId int) error {
 func (s *Service) UpdateItem(
 	ctx context.Context,
 	itemId int,
-	title, description string,
+	title, description, link, author string,
 	pubDate time.Time,
 ) (model.Item, error) {
-	return s.itemRepository.Update(ctx, itemId, title, description, pubDate)
+	return s.itemRepository.Update(ctx, itemId, title, description, link, author, pubDate)
 }
 
-func (s *Service) saveChannels(ctx context.Context, channels []model.Channel) error {
-	return s.storage.WithTransaction(ctx, func(txStorage storage.Interface) error {
-		// Create new repositories with the transaction storage.
-		// It prevents race conditions that can occur when multiple goroutines
-		// try to access the same repository concurrently.
-		channelRepository := s.channelRepositoryFactory.New(txStorage)
-		itemRepository := s.itemRepositoryFactory.New(txStorage)
+// storedValidators returns the cache validators saved by the previous import
+// of the stored channels, if any.
+func storedValidators(stored []model.Channel) fetcher.Validators {
+	if len(stored) == 0 {
+		return fetcher.Validators{}
+	}
+
+	return fetcher.Validators{Etag: stored[0].Etag, LastModified: stored[0].LastModified}
+}
+
+// notModified records the fetch of a feed that the server reports unchanged
+// since the stored channels were imported.
+func (s *Service) notModified(ctx context.Context, url string, stored []model.Channel) (ImportStats, error) {
+	stats := ImportStats{NotModified: true}
 
+	if len(stored) == 0 {
+		return stats, nil
+	}
+
+	return stats, s.markFetched(ctx, s.channelRepository, url, &stored[0])
+}
+
+// markFetched records a successful fetch of the channels imported from url
+// and plans their next refresh from the polling hints of channel.
+func (s *Service) markFetched(
+	ctx context.Context,
+	repo repository.ChannelFetchRecorder,
+	url string,
+	channel *model.Channel,
+) error {
+	now := time.Now()
+
+	return repo.MarkFetched(ctx, url, now, nextFetch(channel, now, s.refreshInterval))
+}
+
+func (s *Service) saveChannels(ctx context.Context, url string, channels []model.Channel) (ImportStats, error) {
+	return s.writeFeed(ctx, url, func(w *feedWriter) error {
 		for i := range channels {
-			channelId, err := channelRepository.Save(ctx, &channels[i])
-			if err != nil {
+			if err := w.channel(ctx, &channels[i]); err != nil {
 				return err
 			}
 
 			for _, item := range channels[i].Items {
-				if err := itemRepository.Save(ctx, item, channelId); err != nil {
+				if err := w.item(ctx, item); err != nil {
 					return err
 				}
 			}
@@ -210,3 +857,89 @@ func (s *Service) saveChannels(ctx context.Context, channels []model.Channel) er
 		return nil
 	})
 }
+
+// writeFeed stores the channels passed by write to the feed writer of url in
+// one transaction.
+func (s *Service) writeFeed(ctx context.Context, url string, write func(w *feedWriter) error) (ImportStats, error) {
+	var stats ImportStats
+
+	err := s.storage.WithTransaction(ctx, func(txStorage storage.Interface) error {
+		// Create new repositories with the transaction storage.
+		// It prevents race conditions that can occur when multiple goroutines
+		// try to access the same repository concurrently.
+		channelRepository := s.channelRepositoryFactory.New(txStorage)
+		itemRepository := s.itemRepositoryFactory.New(txStorage)
+
+		w, err := newFeedWriter(ctx, channelRepository, itemRepository, url)
+		if err != nil {
+			return err
+		}
+
+		if err := write(w); err != nil {
+			return err
+		}
+
+		if err := w.flush(ctx); err != nil {
+			return err
+		}
+
+		if w.channels > 0 {
+			if err := s.markFetched(ctx, channelRepository, url, &w.first); err != nil {
+				return err
+			}
+		}
+
+		stats = w.stats
+
+		return nil
+	})
+	if err != nil {
+		return ImportStats{}, err
+	}
+
+	return stats, nil
+}
+
+func saveItems(
+	ctx context.Context,
+	itemRepository repository.ItemUpserter,
+	channelId int,
+	items []model.Item,
+	matcher *itemMatcher,
+) (ImportStats, error) {
+	var (
+		stats    ImportStats
+		inserted []model.Item
+	)
+
+	for _, item := range items {
+		existing, found, duplicate := matcher.match(item)
+
+		switch {
+		case duplicate:
+			stats.Unchanged++
+		case !found:
+			inserted = append(inserted, item)
+		case itemChanged(existing, item):
+			if err := itemRepository.Replace(ctx, existing.Id, item); err != nil {
+				return ImportStats{}, err
+			}
+
+			stats.Updated++
+		default:
+			stats.Unchanged++
+		}
+	}
+
+	// New items are saved at once, which takes fewer round trips than saving
+	// them one by one.
+	if len(inserted) > 0 {
+		if err := itemRepository.SaveAll(ctx, inserted, channelId); err != nil {
+			return ImportStats{}, err
+		}
+
+		stats.Inserted = len(inserted)
+	}
+
+	return stats, nil
+}
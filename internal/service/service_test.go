@@ -1,15 +1,24 @@
 package service
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/marchuknikolay/rss-parser/internal/fetcher"
 	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+	"github.com/marchuknikolay/rss-parser/internal/repository"
 	repomock "github.com/marchuknikolay/rss-parser/internal/repository/mock"
 	servicemock "github.com/marchuknikolay/rss-parser/internal/service/mock"
 	"github.com/marchuknikolay/rss-parser/internal/storage"
@@ -18,11 +27,23 @@ import (
 
 const rssFeedUrl = "https://test.feed/rss"
 
+func noStoredChannels(context.Context, string) ([]model.Channel, error) {
+	return nil, nil
+}
+
+func lockSourceUrl(context.Context, string) error {
+	return nil
+}
+
+func ignoreMarkFetched(context.Context, string, time.Time, time.Time) error {
+	return nil
+}
+
 func TestService_ImportFeeds(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, nil
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
 			},
 		}
 
@@ -38,6 +59,25 @@ func TestService_ImportFeeds(t *testing.T) {
 
 		mockStorage := repomock.MockStorage{
 			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var savedFolders []string
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			MarkFetchedFunc:    ignoreMarkFetched,
+			LockSourceUrlFunc:  lockSourceUrl,
+			GetBySourceUrlFunc: noStoredChannels,
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				savedFolders = append(savedFolders, ch.Folder)
+
+				return 1, nil
+			},
+		}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
 				return nil
 			},
 		}
@@ -46,29 +86,410 @@ func TestService_ImportFeeds(t *testing.T) {
 			mockFetcher,
 			mockParser,
 			mockStorage,
-			&servicemock.MockChannelRepositoryFactory{},
-			&servicemock.MockItemRepositoryFactory{},
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
 		)
 
-		err := service.ImportFeeds(context.Background(), []string{rssFeedUrl, rssFeedUrl})
+		subscriptions := []Subscription{
+			{Url: rssFeedUrl, Folder: "News/Tech"},
+			{Url: rssFeedUrl, Folder: "Duplicate"},
+		}
+
+		report, err := service.ImportFeeds(context.Background(), subscriptions)
 
 		require.NoError(t, err)
+		require.Equal(t, []string{"News/Tech"}, savedFolders)
+		require.Equal(t, ImportStats{Inserted: 1, ChannelIds: []int{1}}, report.Total)
+		require.Len(t, report.Feeds, 1)
+
+		result := report.Feeds[0]
+
+		require.Equal(t, subscriptions[0], result.Subscription)
+		require.Equal(t, FeedStatusImported, result.Status)
+		require.Equal(t, ImportStats{Inserted: 1, ChannelIds: []int{1}}, result.Stats)
+		require.NoError(t, result.Err)
+		require.Empty(t, result.Error)
+		require.Empty(t, result.ErrorCategory)
 	})
 
 	t.Run("OneImportFailed", func(t *testing.T) {
-		urls := []string{
-			"https://test1.feed/rss",
-			"https://test2.feed/rss",
+		subscriptions := []Subscription{
+			{Url: "https://test1.feed/rss", Folder: "A"},
+			{Url: "https://test2.feed/rss", Folder: "B"},
+		}
+
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				if url == subscriptions[1].Url {
+					return fetcher.Response{}, fmt.Errorf("fetching for url %v failed", url)
+				}
+
+				return fetcher.Response{}, nil
+			},
+		}
+
+		rss := model.Rss{
+			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return rss, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		report, err := service.ImportFeeds(context.Background(), subscriptions)
+
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		require.Len(t, importErr.Errs, 1)
+		require.Len(t, report.Feeds, 2)
+		require.Equal(t, subscriptions[0], report.Feeds[0].Subscription)
+		require.NoError(t, report.Feeds[0].Err)
+		require.Equal(t, subscriptions[1], report.Feeds[1].Subscription)
+		require.ErrorContains(t, report.Feeds[1].Err, "fetching for url https://test2.feed/rss failed")
+		require.Equal(t, FeedStatusFailed, report.Feeds[1].Status)
+		require.Equal(t, "fetching for url https://test2.feed/rss failed", report.Feeds[1].Error)
+		require.Equal(t, ErrorCategoryNetwork, report.Feeds[1].ErrorCategory)
+	})
+
+	t.Run("AllImportsFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, fmt.Errorf("fetching for url %v failed", url)
+			},
 		}
 
+		service := New(
+			mockFetcher,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeeds(context.Background(), []Subscription{{Url: rssFeedUrl}, {Url: rssFeedUrl}})
+
+		require.Error(t, err)
+	})
+
+	t.Run("KeepsErrorTypes", func(t *testing.T) {
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				if url == urls[1] {
-					return nil, fmt.Errorf("fetching for url %v failed", url)
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, &fetcher.RejectedError{
+					Url:    url,
+					Err:    fetcher.ErrUnsupportedContentType,
+					Detail: "video/mp4",
+				}
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeeds(context.Background(), []Subscription{{Url: rssFeedUrl}})
+
+		var importErr *ImportError
+		require.ErrorAs(t, err, &importErr)
+		require.Len(t, importErr.Errs, 1)
+		require.ErrorIs(t, err, fetcher.ErrUnsupportedContentType)
+		require.Contains(t, err.Error(), "failed to import 1 feeds: - URL: "+rssFeedUrl)
+
+		var feedErr *FeedError
+		require.ErrorAs(t, err, &feedErr)
+		require.Equal(t, ErrorCategoryRejected, feedErr.Category)
+	})
+
+	t.Run("CategorizesErrors", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			fetchErr error
+			parseErr error
+			saveErr  error
+			expected ErrorCategory
+		}{
+			{"HttpStatus", &fetcher.StatusError{StatusCode: 404}, nil, nil, ErrorCategoryHttpStatus},
+			{"Parse", nil, errors.New("parsing failed"), nil, ErrorCategoryParse},
+			{"Database", nil, nil, errors.New("saving failed"), ErrorCategoryDatabase},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				mockFetcher := servicemock.MockFetcher{
+					FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+						return fetcher.Response{}, tt.fetchErr
+					},
+				}
+
+				mockParser := servicemock.MockParser{
+					ParseFunc: func(bs []byte) (model.Rss, error) {
+						return model.Rss{Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)}}, tt.parseErr
+					},
+				}
+
+				mockStorage := repomock.MockStorage{
+					WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+						if tt.saveErr != nil {
+							return tt.saveErr
+						}
+
+						return fn(nil)
+					},
 				}
 
+				service := New(
+					mockFetcher,
+					mockParser,
+					mockStorage,
+					&servicemock.MockChannelRepositoryFactory{
+						Repo: &servicemock.MockChannelRepository{
+							LockSourceUrlFunc:  lockSourceUrl,
+							GetBySourceUrlFunc: noStoredChannels,
+						},
+					},
+					&servicemock.MockItemRepositoryFactory{},
+				)
+
+				report, err := service.ImportFeeds(context.Background(), []Subscription{{Url: rssFeedUrl}})
+
+				require.Error(t, err)
+				require.Len(t, report.Feeds, 1)
+				require.Equal(t, FeedStatusFailed, report.Feeds[0].Status)
+				require.Equal(t, tt.expected, report.Feeds[0].ErrorCategory)
+			})
+		}
+	})
+}
+
+func TestService_ImportFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
+		}
+
+		rss := model.Rss{
+			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return rss, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var (
+			lockedUrl      string
+			savedSourceUrl string
+			markedUrl      string
+			markedNext     time.Time
+		)
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			LockSourceUrlFunc: func(ctx context.Context, sourceUrl string) error {
+				lockedUrl = sourceUrl
+
+				return nil
+			},
+			GetBySourceUrlFunc: func(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
 				return nil, nil
 			},
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				savedSourceUrl = ch.SourceUrl
+
+				return 1, nil
+			},
+			MarkFetchedFunc: func(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error {
+				markedUrl, markedNext = sourceUrl, nextFetchAt
+
+				return nil
+			},
+		}
+
+		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
+			Repo: mockChannelRepo,
+		}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				return nil
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			mockChannelFactory,
+			mockItemFactory,
+			WithRefreshInterval(time.Hour),
+		)
+
+		start := time.Now()
+		stats, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, rssFeedUrl, lockedUrl)
+		require.Equal(t, rssFeedUrl, savedSourceUrl)
+		require.Equal(t, ImportStats{Inserted: 1, ChannelIds: []int{1}}, stats)
+		require.Equal(t, rssFeedUrl, markedUrl)
+		// The polling hints of the channel can only make the wait longer.
+		require.False(t, markedNext.Before(start.Add(time.Hour)))
+	})
+
+	t.Run("LockingFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return model.Rss{Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)}}, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		lockErr := errors.New("Locking failed")
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc: func(ctx context.Context, sourceUrl string) error {
+						return lockErr
+					},
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.ErrorIs(t, err, lockErr)
+	})
+
+	t.Run("FetchingFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, errors.New("Fetching failed")
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.Error(t, err)
+	})
+
+	t.Run("ParsingFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func([]byte) (model.Rss, error) {
+				return model.Rss{}, errors.New("Parsing failed")
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: nil},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.Error(t, err)
+	})
+
+	t.Run("ChannelSavingFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
 		}
 
 		rss := model.Rss{
@@ -81,9 +502,660 @@ func TestService_ImportFeeds(t *testing.T) {
 			},
 		}
 
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			LockSourceUrlFunc: lockSourceUrl,
+			GetBySourceUrlFunc: func(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+				return nil, nil
+			},
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				return 0, errors.New("Channel saving failed")
+			},
+		}
+
+		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
+			Repo: mockChannelRepo,
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			mockChannelFactory,
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.Error(t, err)
+	})
+
+	t.Run("ItemSavingFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
+		}
+
+		rss := model.Rss{
+			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return rss, nil
+			},
+		}
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			LockSourceUrlFunc: lockSourceUrl,
+			GetBySourceUrlFunc: func(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+				return nil, nil
+			},
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				return 1, nil
+			},
+		}
+
+		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
+			Repo: mockChannelRepo,
+		}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				return errors.New("Item saving failed")
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		service := New(mockFetcher, mockParser, mockStorage, mockChannelFactory, mockItemFactory)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.Error(t, err)
+	})
+
+	t.Run("Reimport", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, nil
+			},
+		}
+
+		changed := testutils.CreateItemWithId(2)
+		changed.Title = "Item 2 updated"
+
+		rss := model.Rss{
+			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
+		}
+		rss.Channels[0].Items = append(rss.Channels[0].Items, changed, testutils.CreateItemWithId(3))
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return rss, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var replacedChannelId int
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			MarkFetchedFunc:   ignoreMarkFetched,
+			LockSourceUrlFunc: lockSourceUrl,
+			GetBySourceUrlFunc: func(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+				return []model.Channel{testutils.CreateChannelWithId(7)}, nil
+			},
+			ReplaceFunc: func(ctx context.Context, id int, channel *model.Channel) error {
+				replacedChannelId = id
+
+				return nil
+			},
+		}
+
+		var (
+			lookedUp        repository.ItemKeys
+			replacedItemIds []int
+			savedItems      []model.Item
+		)
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			GetByKeysFunc: func(ctx context.Context, channelId int, keys repository.ItemKeys) ([]model.Item, error) {
+				lookedUp = keys
+
+				return []model.Item{testutils.CreateItemWithId(1), testutils.CreateItemWithId(2)}, nil
+			},
+			ReplaceFunc: func(ctx context.Context, id int, item model.Item) error {
+				replacedItemIds = append(replacedItemIds, id)
+
+				return nil
+			},
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				savedItems = append(savedItems, items...)
+
+				return nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
+		)
+
+		stats, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, ImportStats{Inserted: 1, Updated: 1, Unchanged: 1, ChannelIds: []int{7}}, stats)
+		require.Equal(t, 7, replacedChannelId)
+		require.Equal(t, repository.ItemKeys{Guids: []string{"item-1", "item-2", "item-3"}}, lookedUp)
+		require.Equal(t, []int{2}, replacedItemIds)
+		require.Len(t, savedItems, 1)
+		require.Equal(t, "Item 3", savedItems[0].Title)
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		stored := testutils.CreateChannelWithId(1)
+
+		var sentValidators fetcher.Validators
+
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				sentValidators = v
+
+				return fetcher.Response{Validators: v, NotModified: true}, nil
+			},
+		}
+
+		var markedUrl string
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			LockSourceUrlFunc: lockSourceUrl,
+			GetBySourceUrlFunc: func(ctx context.Context, sourceUrl string) ([]model.Channel, error) {
+				return []model.Channel{stored}, nil
+			},
+			MarkFetchedFunc: func(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error {
+				markedUrl = sourceUrl
+
+				return nil
+			},
+		}
+
+		// Parser and storage are nil, so any parsing or saving would panic.
+		service := New(
+			mockFetcher,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		stats, err := service.ImportFeed(context.Background(), stored.SourceUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, ImportStats{NotModified: true}, stats)
+		require.Equal(t, stored.SourceUrl, markedUrl)
+		require.Equal(t, fetcher.Validators{Etag: stored.Etag, LastModified: stored.LastModified}, sentValidators)
+	})
+
+	t.Run("StoresValidators", func(t *testing.T) {
+		validators := fetcher.Validators{Etag: `"v2"`, LastModified: "Mon, 28 Jul 2025 10:45:00 GMT"}
+
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Validators: validators}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return model.Rss{Channels: []model.Channel{testutils.CreateChannelWithItems(1)}}, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var saved fetcher.Validators
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			MarkFetchedFunc:    ignoreMarkFetched,
+			LockSourceUrlFunc:  lockSourceUrl,
+			GetBySourceUrlFunc: noStoredChannels,
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				saved = fetcher.Validators{Etag: ch.Etag, LastModified: ch.LastModified}
+
+				return 1, nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, validators, saved)
+	})
+
+	t.Run("DecompressesBody", func(t *testing.T) {
+		const content = "<rss></rss>"
+
+		var compressed bytes.Buffer
+
+		w := gzip.NewWriter(&compressed)
+		_, err := w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: compressed.Bytes(), ContentEncoding: "gzip"}, nil
+			},
+		}
+
+		var parsed string
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				parsed = string(bs)
+
+				return model.Rss{}, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{},
+		)
+
+		_, err = service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, content, parsed)
+	})
+
+	t.Run("ResolvesRelativeUrls", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				channel := model.Channel{
+					Title: "Channel",
+					Items: []model.Item{{
+						Title:       "Item",
+						Link:        "/posts/1",
+						Description: `<img src="cover.png">`,
+					}},
+				}
+
+				return model.Rss{Channels: []model.Channel{channel}}, nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var saved model.Item
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				saved = items[0]
+
+				return nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					MarkFetchedFunc:    ignoreMarkFetched,
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+					SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+						return 1, nil
+					},
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, "https://test.feed/posts/1", saved.Link)
+		require.Equal(t, `<img src="https://test.feed/posts/cover.png">`, saved.Description)
+	})
+
+	t.Run("StreamsItems", func(t *testing.T) {
+		const itemCount = 2*itemBatchSize + 1
+
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
+		}
+
+		var saved []model.Item
+
+		mockParser := servicemock.MockParser{
+			StreamFunc: func(r io.Reader, h parser.StreamHandler) error {
+				if err := h.Channel(model.Channel{Title: "Channel"}); err != nil {
+					return err
+				}
+
+				for i := range itemCount {
+					item := model.Item{Title: fmt.Sprintf("Item %d", i), Link: fmt.Sprintf("/posts/%d", i)}
+
+					if err := h.Item(item); err != nil {
+						return err
+					}
+
+					// Items are saved in batches while the feed is decoded.
+					require.Len(t, saved, i+1-(i+1)%itemBatchSize)
+				}
+
+				return nil
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		batches := 0
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				saved = append(saved, items...)
+				batches++
+
+				return nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					MarkFetchedFunc:    ignoreMarkFetched,
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+					SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+						return 1, nil
+					},
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
+		)
+
+		stats, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, ImportStats{Inserted: itemCount, ChannelIds: []int{1}}, stats)
+		require.Len(t, saved, itemCount)
+		require.Equal(t, 3, batches)
+		require.Equal(t, "https://test.feed/posts/0", saved[0].Link)
+	})
+
+	t.Run("KeepsTrailingChannelMetadata", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			StreamFunc: func(r io.Reader, h parser.StreamHandler) error {
+				if err := h.Channel(model.Channel{Title: "Channel"}); err != nil {
+					return err
+				}
+
+				if err := h.Item(model.Item{Title: "Item", Link: "/posts/1"}); err != nil {
+					return err
+				}
+
+				return h.ChannelUpdate(model.Channel{Title: "Channel", Ttl: 180, ImageUrl: "/logo.png"})
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		var (
+			replaced   *model.Channel
+			markedNext time.Time
+		)
+
+		mockChannelRepo := &servicemock.MockChannelRepository{
+			LockSourceUrlFunc:  lockSourceUrl,
+			GetBySourceUrlFunc: noStoredChannels,
+			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				return 1, nil
+			},
+			ReplaceFunc: func(ctx context.Context, id int, ch *model.Channel) error {
+				require.Equal(t, 1, id)
+				replaced = ch
+
+				return nil
+			},
+			MarkFetchedFunc: func(ctx context.Context, sourceUrl string, fetchedAt, nextFetchAt time.Time) error {
+				markedNext = nextFetchAt
+
+				return nil
+			},
+		}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
+				return nil
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
+			WithRefreshInterval(time.Hour),
+		)
+
+		start := time.Now()
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.NotNil(t, replaced)
+		require.Equal(t, 180, replaced.Ttl)
+		require.Equal(t, "https://test.feed/logo.png", replaced.ImageUrl)
+		require.Equal(t, rssFeedUrl, replaced.SourceUrl)
+		// The next refresh follows the ttl that came after the items.
+		require.False(t, markedNext.Before(start.Add(3*time.Hour)))
+	})
+
+	t.Run("StreamingStorageFailed", func(t *testing.T) {
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
+		}
+
+		// Parse isn't mocked, so falling back to it would fail differently.
+		mockParser := servicemock.MockParser{
+			StreamFunc: func(r io.Reader, h parser.StreamHandler) error {
+				return h.Channel(model.Channel{Title: "Channel"})
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		errSaving := errors.New("Channel saving failed")
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+					SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+						return 0, errSaving
+					},
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: nil},
+		)
+
+		_, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.ErrorIs(t, err, errSaving)
+	})
+
+	t.Run("StreamsOpenedBody", func(t *testing.T) {
+		const body = "<rss><channel><title>Channel</title></channel></rss>"
+
+		// Fetch isn't mocked, so the body has to be read from Open.
+		mockFetcher := servicemock.MockFetcher{
+			OpenFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Stream, error) {
+				return fetcher.Stream{Body: io.NopCloser(strings.NewReader(body))}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			StreamFunc: func(r io.Reader, h parser.StreamHandler) error {
+				bs, err := io.ReadAll(r)
+				require.NoError(t, err)
+				require.Equal(t, body, string(bs))
+
+				return h.Channel(model.Channel{Title: "Channel"})
+			},
+		}
+
+		mockStorage := repomock.MockStorage{
+			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
+				return fn(nil)
+			},
+		}
+
+		service := New(
+			mockFetcher,
+			mockParser,
+			mockStorage,
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					MarkFetchedFunc:    ignoreMarkFetched,
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+					SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+						return 1, nil
+					},
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: &servicemock.MockItemRepository{}},
+		)
+
+		stats, err := service.ImportFeed(context.Background(), rssFeedUrl)
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1}, stats.ChannelIds)
+	})
+
+	t.Run("FetchesAgainWhenStreamingFails", func(t *testing.T) {
+		errReading := errors.New("connection reset")
+		fetched := 0
+
+		mockFetcher := servicemock.MockFetcher{
+			OpenFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Stream, error) {
+				return fetcher.Stream{Body: io.NopCloser(io.MultiReader(
+					strings.NewReader("<rss>"),
+					iotest.ErrReader(errReading),
+				))}, nil
+			},
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				fetched++
+
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
+		}
+
+		mockParser := servicemock.MockParser{
+			StreamFunc: func(r io.Reader, h parser.StreamHandler) error {
+				_, err := io.ReadAll(r)
+
+				return err
+			},
+			ParseFunc: func([]byte) (model.Rss, error) {
+				return model.Rss{Channels: []model.Channel{{Title: "Channel"}}}, nil
+			},
+		}
+
 		mockStorage := repomock.MockStorage{
 			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
-				return nil
+				return fn(nil)
 			},
 		}
 
@@ -91,51 +1163,53 @@ func TestService_ImportFeeds(t *testing.T) {
 			mockFetcher,
 			mockParser,
 			mockStorage,
-			&servicemock.MockChannelRepositoryFactory{},
-			&servicemock.MockItemRepositoryFactory{},
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					MarkFetchedFunc:    ignoreMarkFetched,
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+					SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+						return 1, nil
+					},
+				},
+			},
+			&servicemock.MockItemRepositoryFactory{Repo: &servicemock.MockItemRepository{}},
 		)
 
-		err := service.ImportFeeds(context.Background(), urls)
+		stats, err := service.ImportFeed(context.Background(), rssFeedUrl)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.Equal(t, 1, fetched)
+		require.Equal(t, []int{1}, stats.ChannelIds)
 	})
 
-	t.Run("AllImportsFailed", func(t *testing.T) {
-		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, fmt.Errorf("fetching for url %v failed", url)
-			},
-		}
-
-		service := New(
-			mockFetcher,
-			nil,
-			nil,
-			&servicemock.MockChannelRepositoryFactory{},
-			&servicemock.MockItemRepositoryFactory{},
+	t.Run("DiscoversFeed", func(t *testing.T) {
+		const (
+			pageUrl = "https://example.com/blog/"
+			feedUrl = "https://example.com/blog/feed.xml"
 		)
 
-		err := service.ImportFeeds(context.Background(), []string{rssFeedUrl, rssFeedUrl})
+		page := `<!DOCTYPE html><html><head>
+			<link rel="alternate" type="application/rss+xml" href="feed.xml">
+			</head><body></body></html>`
 
-		require.Error(t, err)
-	})
-}
+		var fetched []string
 
-func TestService_ImportFeed(t *testing.T) {
-	t.Run("Success", func(t *testing.T) {
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, nil
-			},
-		}
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				fetched = append(fetched, url)
 
-		rss := model.Rss{
-			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
+				if url == pageUrl {
+					return fetcher.Response{Body: []byte(page)}, nil
+				}
+
+				return fetcher.Response{Body: []byte("<rss></rss>")}, nil
+			},
 		}
 
 		mockParser := servicemock.MockParser{
 			ParseFunc: func(bs []byte) (model.Rss, error) {
-				return rss, nil
+				return model.Rss{Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)}}, nil
 			},
 		}
 
@@ -145,69 +1219,88 @@ func TestService_ImportFeed(t *testing.T) {
 			},
 		}
 
+		var savedSourceUrl string
+
 		mockChannelRepo := &servicemock.MockChannelRepository{
+			MarkFetchedFunc:    ignoreMarkFetched,
+			LockSourceUrlFunc:  lockSourceUrl,
+			GetBySourceUrlFunc: noStoredChannels,
 			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
+				savedSourceUrl = ch.SourceUrl
+
 				return 1, nil
 			},
 		}
 
-		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
-			Repo: mockChannelRepo,
-		}
-
 		mockItemRepo := &servicemock.MockItemRepository{
-			SaveFunc: func(ctx context.Context, item model.Item, channelId int) error {
+			SaveAllFunc: func(ctx context.Context, items []model.Item, channelId int) error {
 				return nil
 			},
 		}
 
-		mockItemFactory := &servicemock.MockItemRepositoryFactory{
-			Repo: mockItemRepo,
-		}
-
 		service := New(
 			mockFetcher,
 			mockParser,
 			mockStorage,
-			mockChannelFactory,
-			mockItemFactory,
+			&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+			&servicemock.MockItemRepositoryFactory{Repo: mockItemRepo},
 		)
 
-		err := service.ImportFeed(context.Background(), rssFeedUrl)
+		stats, err := service.ImportFeed(context.Background(), pageUrl)
 
 		require.NoError(t, err)
+		require.Equal(t, []string{pageUrl, feedUrl}, fetched)
+		require.Equal(t, feedUrl, savedSourceUrl)
+		require.Equal(t, 1, stats.Inserted)
+		require.Equal(t, []Discovery{{PageUrl: pageUrl, FeedUrl: feedUrl}}, stats.Discovered)
 	})
 
-	t.Run("FetchingFailed", func(t *testing.T) {
+	t.Run("NoFeedDiscovered", func(t *testing.T) {
+		const pageUrl = "https://example.com/"
+
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, errors.New("Fetching failed")
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				if url == pageUrl {
+					return fetcher.Response{Body: []byte("<html><body>Hello</body></html>")}, nil
+				}
+
+				return fetcher.Response{}, errors.New("unexpected status code: 404")
 			},
 		}
 
 		service := New(
 			mockFetcher,
-			nil,
-			nil,
-			&servicemock.MockChannelRepositoryFactory{},
+			servicemock.MockParser{},
+			repomock.MockStorage{},
+			&servicemock.MockChannelRepositoryFactory{
+				Repo: &servicemock.MockChannelRepository{
+					LockSourceUrlFunc:  lockSourceUrl,
+					GetBySourceUrlFunc: noStoredChannels,
+				},
+			},
 			&servicemock.MockItemRepositoryFactory{},
 		)
 
-		err := service.ImportFeed(context.Background(), rssFeedUrl)
+		stats, err := service.ImportFeed(context.Background(), pageUrl)
 
-		require.Error(t, err)
+		require.ErrorIs(t, err, ErrFeedNotFound)
+		require.Equal(t, ImportStats{}, stats)
 	})
+}
 
-	t.Run("ParsingFailed", func(t *testing.T) {
+func TestService_ValidateFeed(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, nil
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				require.Equal(t, fetcher.Validators{}, v)
+
+				return fetcher.Response{Body: []byte(`<rss><channel/></rss>`)}, nil
 			},
 		}
 
 		mockParser := servicemock.MockParser{
-			ParseFunc: func([]byte) (model.Rss, error) {
-				return model.Rss{}, errors.New("Parsing failed")
+			ParseFunc: func(bs []byte) (model.Rss, error) {
+				return model.Rss{Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1, 2)}}, nil
 			},
 		}
 
@@ -215,109 +1308,60 @@ func TestService_ImportFeed(t *testing.T) {
 			mockFetcher,
 			mockParser,
 			nil,
-			&servicemock.MockChannelRepositoryFactory{Repo: nil},
-			&servicemock.MockItemRepositoryFactory{Repo: nil},
+			&servicemock.MockChannelRepositoryFactory{},
+			&servicemock.MockItemRepositoryFactory{},
 		)
 
-		err := service.ImportFeed(context.Background(), rssFeedUrl)
+		report, err := service.ValidateFeed(context.Background(), rssFeedUrl)
 
-		require.Error(t, err)
+		require.NoError(t, err)
+		require.True(t, report.Valid)
+		require.Equal(t, rssFeedUrl, report.Url)
+		require.Equal(t, parser.FormatRss, report.Format)
+		require.Equal(t, 2, report.ItemCount)
 	})
 
-	t.Run("ChannelSavingFailed", func(t *testing.T) {
+	t.Run("FetchError", func(t *testing.T) {
 		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, nil
-			},
-		}
-
-		rss := model.Rss{
-			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
-		}
-
-		mockParser := servicemock.MockParser{
-			ParseFunc: func(bs []byte) (model.Rss, error) {
-				return rss, nil
-			},
-		}
-
-		mockChannelRepo := &servicemock.MockChannelRepository{
-			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
-				return 0, errors.New("Channel saving failed")
-			},
-		}
-
-		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
-			Repo: mockChannelRepo,
-		}
-
-		mockStorage := repomock.MockStorage{
-			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
-				return fn(nil)
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{}, errors.New("fetching failed")
 			},
 		}
 
 		service := New(
 			mockFetcher,
-			mockParser,
-			mockStorage,
-			mockChannelFactory,
+			servicemock.MockParser{},
+			nil,
+			&servicemock.MockChannelRepositoryFactory{},
 			&servicemock.MockItemRepositoryFactory{},
 		)
 
-		err := service.ImportFeed(context.Background(), rssFeedUrl)
+		_, err := service.ValidateFeed(context.Background(), rssFeedUrl)
 
 		require.Error(t, err)
 	})
+}
 
-	t.Run("ItemSavingFailed", func(t *testing.T) {
-		mockFetcher := servicemock.MockFetcher{
-			FetchFunc: func(ctx context.Context, url string) ([]byte, error) {
-				return nil, nil
-			},
-		}
-
-		rss := model.Rss{
-			Channels: []model.Channel{testutils.CreateChannelWithItems(1, 1)},
-		}
-
-		mockParser := servicemock.MockParser{
-			ParseFunc: func(bs []byte) (model.Rss, error) {
-				return rss, nil
-			},
-		}
-
-		mockChannelRepo := &servicemock.MockChannelRepository{
-			SaveFunc: func(ctx context.Context, ch *model.Channel) (int, error) {
-				return 1, nil
-			},
-		}
-
-		mockChannelFactory := &servicemock.MockChannelRepositoryFactory{
-			Repo: mockChannelRepo,
-		}
-
-		mockItemRepo := &servicemock.MockItemRepository{
-			SaveFunc: func(ctx context.Context, ch model.Item, channelId int) error {
-				return errors.New("Item saving failed")
-			},
-		}
+func TestValidateFeed(t *testing.T) {
+	t.Run("DecodedTooLarge", func(t *testing.T) {
+		var buf bytes.Buffer
 
-		mockItemFactory := &servicemock.MockItemRepositoryFactory{
-			Repo: mockItemRepo,
-		}
+		gz := gzip.NewWriter(&buf)
+		_, err := gz.Write([]byte(`<rss><channel><title>Channel 1</title></channel></rss>`))
+		require.NoError(t, err)
+		require.NoError(t, gz.Close())
 
-		mockStorage := repomock.MockStorage{
-			WithTransactionFunc: func(ctx context.Context, fn func(storage.Interface) error) error {
-				return fn(nil)
+		mockFetcher := servicemock.MockFetcher{
+			FetchFunc: func(ctx context.Context, url string, v fetcher.Validators) (fetcher.Response, error) {
+				return fetcher.Response{Body: buf.Bytes(), ContentEncoding: "gzip"}, nil
 			},
 		}
 
-		service := New(mockFetcher, mockParser, mockStorage, mockChannelFactory, mockItemFactory)
-
-		err := service.ImportFeed(context.Background(), rssFeedUrl)
+		_, err = ValidateFeed(context.Background(), mockFetcher, servicemock.MockParser{}, 10, rssFeedUrl)
 
-		require.Error(t, err)
+		var feedErr *FeedError
+		require.ErrorAs(t, err, &feedErr)
+		require.Equal(t, ErrorCategoryParse, feedErr.Category)
 	})
 }
 
@@ -591,7 +1635,10 @@ func TestService_UpdateChannel(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		mockChannelRepo := &servicemock.MockChannelRepository{
 			UpdateFunc: func(ctx context.Context, id int, title, language, description string) (model.Channel, error) {
-				return model.Channel{Id: id, Title: title, Language: language, Description: description}, nil
+				channel := testutils.CreateChannelWithId(id)
+				channel.Title, channel.Language, channel.Description = title, language, description
+
+				return channel, nil
 			},
 		}
 
@@ -657,6 +1704,44 @@ func TestService_UpdateChannel(t *testing.T) {
 	})
 }
 
+func TestNewFeedResult(t *testing.T) {
+	t.Run("DurationInMilliseconds", func(t *testing.T) {
+		result := newFeedResult(Subscription{Url: rssFeedUrl}, ImportStats{}, 1500*time.Microsecond, nil)
+
+		bs, err := json.Marshal(result)
+
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Millisecond, result.Duration)
+		require.Contains(t, string(bs), `"DurationMs":2`)
+		require.NotContains(t, string(bs), `"Duration"`)
+	})
+}
+
+func TestService_RecordFetchFailure(t *testing.T) {
+	var reason string
+
+	mockChannelRepo := &servicemock.MockChannelRepository{
+		MarkFailedFunc: func(ctx context.Context, sourceUrl string, at, next time.Time, r string) error {
+			reason = r
+
+			return nil
+		},
+	}
+
+	service := New(
+		nil,
+		nil,
+		nil,
+		&servicemock.MockChannelRepositoryFactory{Repo: mockChannelRepo},
+		&servicemock.MockItemRepositoryFactory{},
+	)
+
+	err := service.RecordFetchFailure(context.Background(), rssFeedUrl, time.Now(), time.Now(), "timeout")
+
+	require.NoError(t, err)
+	require.Equal(t, "timeout", reason)
+}
+
 func TestService_GetItems(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		expected := []model.Item{testutils.CreateItemWithId(1)}
@@ -765,6 +1850,116 @@ func TestService_GetItemsByChannelId(t *testing.T) {
 	})
 }
 
+func TestService_GetItemsByCategory(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.Item{testutils.CreateItemWithId(1)}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			GetByCategoryFunc: func(ctx context.Context, category string) ([]model.Item, error) {
+				require.Equal(t, "golang", category)
+
+				return expected, nil
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		service := New(
+			nil,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{},
+			mockItemFactory,
+		)
+
+		actual, err := service.GetItemsByCategory(context.Background(), " golang ")
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockItemRepo := &servicemock.MockItemRepository{
+			GetByCategoryFunc: func(ctx context.Context, category string) ([]model.Item, error) {
+				return nil, errors.New("Getting items by category failed")
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		service := New(
+			nil,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{},
+			mockItemFactory,
+		)
+
+		items, err := service.GetItemsByCategory(context.Background(), " golang ")
+
+		require.Error(t, err)
+		require.Nil(t, items)
+	})
+}
+
+func TestService_GetCategories(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		expected := []model.CategoryCount{{Name: "golang", Count: 2}}
+
+		mockItemRepo := &servicemock.MockItemRepository{
+			GetCategoriesFunc: func(ctx context.Context) ([]model.CategoryCount, error) {
+				return expected, nil
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		service := New(
+			nil,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{},
+			mockItemFactory,
+		)
+
+		actual, err := service.GetCategories(context.Background())
+
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("RepositoryError", func(t *testing.T) {
+		mockItemRepo := &servicemock.MockItemRepository{
+			GetCategoriesFunc: func(ctx context.Context) ([]model.CategoryCount, error) {
+				return nil, errors.New("Getting categories failed")
+			},
+		}
+
+		mockItemFactory := &servicemock.MockItemRepositoryFactory{
+			Repo: mockItemRepo,
+		}
+
+		service := New(
+			nil,
+			nil,
+			nil,
+			&servicemock.MockChannelRepositoryFactory{},
+			mockItemFactory,
+		)
+
+		categories, err := service.GetCategories(context.Background())
+
+		require.Error(t, err)
+		require.Nil(t, categories)
+	})
+}
+
 func TestService_GetItemById(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
 		id := 1
@@ -874,9 +2069,25 @@ func TestService_DeleteItem(t *testing.T) {
 
 func TestService_UpdateItem(t *testing.T) {
 	t.Run("Success", func(t *testing.T) {
+		expected := testutils.CreateItemWithId(1)
+
 		mockItemRepo := &servicemock.MockItemRepository{
-			UpdateFunc: func(ctx context.Context, id int, title, description string, pubDate time.Time) (model.Item, error) {
-				return model.Item{Id: id, Title: title, Description: description, PubDate: model.DateTime(pubDate)}, nil
+			UpdateFunc: func(
+				ctx context.Context,
+				id int,
+				title, description, link, author string,
+				pubDate time.Time,
+			) (model.Item, error) {
+				// Fields that can't be edited are kept as stored.
+				updated := expected
+				updated.Id = id
+				updated.Title = title
+				updated.Description = description
+				updated.PubDate = model.DateTime(pubDate)
+				updated.Link = link
+				updated.Author = author
+
+				return updated, nil
 			},
 		}
 
@@ -892,13 +2103,13 @@ func TestService_UpdateItem(t *testing.T) {
 			mockItemFactory,
 		)
 
-		expected := testutils.CreateItemWithId(1)
-
 		actual, err := service.UpdateItem(
 			context.Background(),
 			expected.Id,
 			expected.Title,
 			expected.Description,
+			expected.Link,
+			expected.Author,
 			time.Time(expected.PubDate),
 		)
 
@@ -910,7 +2121,12 @@ func TestService_UpdateItem(t *testing.T) {
 		expected := model.Item{}
 
 		mockItemRepo := &servicemock.MockItemRepository{
-			UpdateFunc: func(ctx context.Context, id int, title, description string, pubDate time.Time) (model.Item, error) {
+			UpdateFunc: func(
+				ctx context.Context,
+				id int,
+				title, description, link, author string,
+				pubDate time.Time,
+			) (model.Item, error) {
 				return expected, errors.New("Updating item failed")
 			},
 		}
@@ -934,6 +2150,8 @@ func TestService_UpdateItem(t *testing.T) {
 			item.Id,
 			item.Title,
 			item.Description,
+			item.Link,
+			item.Author,
 			time.Time(item.PubDate),
 		)
 
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE items
+ADD COLUMN link TEXT NOT NULL DEFAULT '',
+ADD COLUMN guid TEXT NOT NULL DEFAULT '',
+ADD COLUMN guid_is_permalink BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN author TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE items
+DROP COLUMN link,
+DROP COLUMN guid,
+DROP COLUMN guid_is_permalink,
+DROP COLUMN author;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE channels
+ADD COLUMN source_url TEXT NOT NULL DEFAULT '',
+ADD COLUMN link TEXT NOT NULL DEFAULT '',
+ADD COLUMN image_url TEXT NOT NULL DEFAULT '',
+ADD COLUMN last_build_date TIMESTAMP,
+ADD COLUMN ttl INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN generator TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX channels_source_url_idx ON channels (source_url);
+
+-- +goose Down
+DROP INDEX channels_source_url_idx;
+
+ALTER TABLE channels
+DROP COLUMN source_url,
+DROP COLUMN link,
+DROP COLUMN image_url,
+DROP COLUMN last_build_date,
+DROP COLUMN ttl,
+DROP COLUMN generator;
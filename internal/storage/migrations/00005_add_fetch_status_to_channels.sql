@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE channels
+ADD COLUMN last_fetched_at TIMESTAMP,
+ADD COLUMN last_error_at TIMESTAMP,
+ADD COLUMN last_error TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE channels
+DROP COLUMN last_fetched_at,
+DROP COLUMN last_error_at,
+DROP COLUMN last_error;
@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE channels
+ADD COLUMN skip_hours INTEGER[],
+ADD COLUMN skip_days TEXT[],
+ADD COLUMN update_period TEXT NOT NULL DEFAULT '',
+ADD COLUMN update_frequency INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN next_fetch_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE channels
+DROP COLUMN skip_hours,
+DROP COLUMN skip_days,
+DROP COLUMN update_period,
+DROP COLUMN update_frequency,
+DROP COLUMN next_fetch_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE channels
+ADD COLUMN folder TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE channels
+DROP COLUMN folder;
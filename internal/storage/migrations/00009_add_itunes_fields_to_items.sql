@@ -0,0 +1,15 @@
+-- +goose Up
+ALTER TABLE items
+ADD COLUMN duration INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN episode INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN season INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN image_url TEXT NOT NULL DEFAULT '',
+ADD COLUMN explicit BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE items
+DROP COLUMN duration,
+DROP COLUMN episode,
+DROP COLUMN season,
+DROP COLUMN image_url,
+DROP COLUMN explicit;
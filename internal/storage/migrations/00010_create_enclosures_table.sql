@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE enclosures (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    length BIGINT NOT NULL DEFAULT 0,
+    type TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX enclosures_item_id_idx ON enclosures (item_id);
+
+-- +goose Down
+DROP TABLE enclosures;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE items
+ADD COLUMN thumbnail_url TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE items
+DROP COLUMN thumbnail_url;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE attachments (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    type TEXT NOT NULL DEFAULT '',
+    medium TEXT NOT NULL DEFAULT '',
+    width INTEGER NOT NULL DEFAULT 0,
+    height INTEGER NOT NULL DEFAULT 0,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    duration INTEGER NOT NULL DEFAULT 0,
+    description TEXT NOT NULL DEFAULT '',
+    credit TEXT NOT NULL DEFAULT '',
+    thumbnail_url TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX attachments_item_id_idx ON attachments (item_id);
+
+-- +goose Down
+DROP TABLE attachments;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE items
+ADD COLUMN content TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE items
+DROP COLUMN content;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    domain TEXT NOT NULL DEFAULT '',
+    UNIQUE (name, domain)
+);
+
+CREATE INDEX categories_lower_name_idx ON categories (lower(name));
+
+CREATE TABLE item_categories (
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (item_id, category_id)
+);
+
+CREATE INDEX item_categories_category_id_idx ON item_categories (category_id);
+
+CREATE TABLE channel_categories (
+    channel_id INTEGER NOT NULL REFERENCES channels(id) ON DELETE CASCADE,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (channel_id, category_id)
+);
+
+CREATE INDEX channel_categories_category_id_idx ON channel_categories (category_id);
+
+-- +goose Down
+DROP TABLE channel_categories;
+DROP TABLE item_categories;
+DROP TABLE categories;
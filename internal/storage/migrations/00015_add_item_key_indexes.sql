@@ -0,0 +1,7 @@
+-- +goose Up
+CREATE INDEX items_channel_id_guid_idx ON items (channel_id, guid);
+CREATE INDEX items_channel_id_link_idx ON items (channel_id, link);
+
+-- +goose Down
+DROP INDEX items_channel_id_link_idx;
+DROP INDEX items_channel_id_guid_idx;
@@ -19,9 +19,15 @@ type CommandExecutor interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (commandTag pgconn.CommandTag, err error)
 }
 
+// BatchSender sends queued statements to the database in one round trip.
+type BatchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
 type Interface interface {
 	QueryExecutor() RowQueryer
 	ExecExecutor() CommandExecutor
+	BatchExecutor() BatchSender
 
 	WithTransaction(ctx context.Context, fn func(txStorage Interface) error) error
 	Close()
@@ -88,3 +94,11 @@ func (s *Storage) ExecExecutor() CommandExecutor {
 
 	return s.Pool
 }
+
+func (s *Storage) BatchExecutor() BatchSender {
+	if s.Tx != nil {
+		return s.Tx
+	}
+
+	return s.Pool
+}
@@ -11,10 +11,24 @@ const utcPlus3Offset = 3 * 60 * 60
 
 func CreateChannelWithId(id int) model.Channel {
 	return model.Channel{
-		Id:          id,
-		Title:       fmt.Sprintf("Channel %v", id),
-		Language:    "en",
-		Description: fmt.Sprintf("Channel %v description", id),
+		Id:              id,
+		Title:           fmt.Sprintf("Channel %v", id),
+		Language:        "en",
+		Description:     fmt.Sprintf("Channel %v description", id),
+		SourceUrl:       fmt.Sprintf("https://example.com/channels/%v/feed.xml", id),
+		Link:            fmt.Sprintf("https://example.com/channels/%v", id),
+		ImageUrl:        fmt.Sprintf("https://example.com/channels/%v/logo.png", id),
+		LastBuildDate:   model.DateTime(time.Date(2025, 7, 27, 13, 45, 0, 0, time.FixedZone("UTC+3", utcPlus3Offset))),
+		Ttl:             60,
+		Generator:       "Generator",
+		SkipHours:       []int{0, 1},
+		SkipDays:        []string{"Sunday"},
+		UpdatePeriod:    "daily",
+		UpdateFrequency: 1,
+		Etag:            fmt.Sprintf(`"etag-%v"`, id),
+		LastModified:    "Sun, 27 Jul 2025 10:45:00 GMT",
+		Folder:          "News/Tech",
+		Categories:      []model.Category{{Name: "Technology"}},
 	}
 }
 
@@ -23,7 +37,35 @@ func CreateItemWithId(id int) model.Item {
 		Id:          id,
 		Title:       fmt.Sprintf("Item %v", id),
 		Description: fmt.Sprintf("Item %v description", id),
+		Content:     fmt.Sprintf("<p>Item %v content</p>", id),
 		PubDate:     model.DateTime(time.Date(2025, 7, 27, 13, 45, 0, 0, time.FixedZone("UTC+3", utcPlus3Offset))),
+		Link:        fmt.Sprintf("https://example.com/items/%v", id),
+		Guid:        model.Guid{Value: fmt.Sprintf("item-%v", id), IsPermaLink: false},
+		Author:      fmt.Sprintf("Author %v", id),
+		Enclosures: []model.Enclosure{
+			{Url: fmt.Sprintf("https://example.com/items/%v.mp3", id), Type: "audio/mpeg", Length: 1024},
+		},
+		Duration: 1800,
+		Episode:  id,
+		Season:   1,
+		ImageUrl: fmt.Sprintf("https://example.com/items/%v.jpg", id),
+		Explicit: false,
+		Attachments: []model.Attachment{
+			{
+				Url:          fmt.Sprintf("https://example.com/items/%v.mp4", id),
+				Type:         "video/mp4",
+				Medium:       "video",
+				Width:        1280,
+				Height:       720,
+				FileSize:     2048,
+				Duration:     60,
+				Description:  fmt.Sprintf("Item %v video", id),
+				Credit:       fmt.Sprintf("Author %v", id),
+				ThumbnailUrl: fmt.Sprintf("https://example.com/items/%v-video.jpg", id),
+			},
+		},
+		ThumbnailUrl: fmt.Sprintf("https://example.com/items/%v-thumbnail.jpg", id),
+		Categories:   []model.Category{{Name: "golang", Domain: "https://example.com/tags"}},
 	}
 }
 
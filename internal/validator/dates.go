@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+)
+
+// checkDates reports the dates the parser can't read. They are dropped while
+// parsing, so they have to be looked up in the document itself.
+func (r *Report) checkDates(bs []byte) {
+	switch r.Format {
+	case parser.FormatJson:
+		r.checkJsonDates(bs)
+	case parser.FormatAtom:
+		r.checkXmlDates(bs, []string{"published", "updated"}, "entry")
+	case parser.FormatRdf:
+		r.checkXmlDates(bs, []string{"date"}, "item")
+	default:
+		r.checkXmlDates(bs, []string{"pubDate", "lastBuildDate"}, "item")
+	}
+}
+
+func (r *Report) checkDate(path, value string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	if _, err := model.ParseDateTime(value); err != nil {
+		r.addWarning(path, fmt.Sprintf("%v, the date is ignored", err))
+	}
+}
+
+// checkXmlDates walks the elements of the document, naming them in paths
+// like the rest of the report does: items are always numbered, other
+// elements only when they are repeated.
+func (r *Report) checkXmlDates(bs []byte, dateNames []string, itemName string) {
+	d := xml.NewDecoder(bytes.NewReader(bs))
+	d.CharsetReader = decoder.CharsetReader
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+
+	type element struct {
+		path     string
+		children map[string]int
+	}
+
+	stack := []element{{children: make(map[string]int)}}
+
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			// Malformed documents are already reported by the parser.
+			return
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := &stack[len(stack)-1]
+			name := t.Name.Local
+			parent.children[name]++
+
+			step := name
+			if n := parent.children[name]; n > 1 || name == itemName {
+				step = fmt.Sprintf("%v[%v]", name, n)
+			}
+
+			path := parent.path + "/" + step
+
+			if !slices.Contains(dateNames, name) {
+				stack = append(stack, element{path: path, children: make(map[string]int)})
+
+				continue
+			}
+
+			var value string
+			if err := d.DecodeElement(&value, &t); err != nil {
+				return
+			}
+
+			r.checkDate(path, value)
+		case xml.EndElement:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+}
+
+func (r *Report) checkJsonDates(bs []byte) {
+	var feed struct {
+		Items []struct {
+			DatePublished string `json:"date_published"`
+			DateModified  string `json:"date_modified"`
+		} `json:"items"`
+	}
+
+	if err := json.Unmarshal(bs, &feed); err != nil {
+		return
+	}
+
+	for i, item := range feed.Items {
+		path := fmt.Sprintf("/items[%v]", i+1)
+
+		r.checkDate(path+"/date_published", item.DatePublished)
+		r.checkDate(path+"/date_modified", item.DateModified)
+	}
+}
@@ -0,0 +1,101 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+)
+
+// paths names the elements of a feed format in issue paths.
+type paths struct {
+	// root is the path of the document element, channelName the channel
+	// element inside it. Formats without a channel element leave it empty.
+	root        string
+	channelName string
+	// itemsInRoot is set when items are siblings of the channel rather than
+	// its children.
+	itemsInRoot bool
+	itemName    string
+
+	// channelLink and image are relative to the channel, the rest to an
+	// item.
+	channelLink string
+	image       string
+	link        string
+	guid        string
+	enclosure   string
+}
+
+func pathsFor(format string) paths {
+	switch format {
+	case parser.FormatAtom:
+		return paths{
+			root:        "/feed",
+			itemName:    "entry",
+			channelLink: "link/@href",
+			image:       "logo",
+			link:        "link/@href",
+			guid:        "id",
+			enclosure:   `link[@rel="enclosure"]/@href`,
+		}
+	case parser.FormatRdf:
+		return paths{
+			root:        "/RDF",
+			channelName: "channel",
+			itemsInRoot: true,
+			itemName:    "item",
+			channelLink: "link",
+			image:       "image/url",
+			link:        "link",
+			guid:        "@about",
+		}
+	case parser.FormatJson:
+		return paths{
+			itemName:    "items",
+			channelLink: "home_page_url",
+			image:       "icon",
+			link:        "url",
+			guid:        "id",
+			enclosure:   "attachments/url",
+		}
+	default:
+		return paths{
+			root:        "/rss",
+			channelName: "channel",
+			itemName:    "item",
+			channelLink: "link",
+			image:       "image/url",
+			link:        "link",
+			guid:        "guid",
+			enclosure:   "enclosure/@url",
+		}
+	}
+}
+
+// channel returns the path of the i-th channel, counted from 0. Like in the
+// paths of the date check, an index is only added to repeated elements.
+func (ps paths) channel(i int) string {
+	if ps.channelName == "" {
+		return ps.root
+	}
+
+	return ps.root + "/" + indexed(ps.channelName, i)
+}
+
+// item returns the path of the j-th item of the i-th channel, counted from 0.
+func (ps paths) item(i, j int) string {
+	parent := ps.channel(i)
+	if ps.itemsInRoot {
+		parent = ps.root
+	}
+
+	return fmt.Sprintf("%v/%v[%v]", parent, ps.itemName, j+1)
+}
+
+func indexed(name string, i int) string {
+	if i == 0 {
+		return name
+	}
+
+	return fmt.Sprintf("%v[%v]", name, i+1)
+}
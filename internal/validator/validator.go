@@ -0,0 +1,226 @@
+package validator
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/marchuknikolay/rss-parser/internal/decoder"
+	"github.com/marchuknikolay/rss-parser/internal/discovery"
+	"github.com/marchuknikolay/rss-parser/internal/model"
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+)
+
+type Severity string
+
+const (
+	// SeverityError marks a problem that makes the feed fail to import or
+	// lose data.
+	SeverityError Severity = "error"
+	// SeverityWarning marks a problem the importer works around.
+	SeverityWarning Severity = "warning"
+)
+
+// Issue is a problem found in a feed. Path points at the element it was
+// found in, e.g. /rss/channel/item[2]/pubDate, with items counted from 1.
+// JSON feeds use the same notation for their properties.
+type Issue struct {
+	Severity Severity
+	Path     string
+	Message  string
+}
+
+// Channel is the metadata of a channel of the validated feed.
+type Channel struct {
+	Title       string
+	Link        string
+	Description string
+	Language    string
+	Generator   string
+	ImageUrl    string
+	ItemCount   int
+}
+
+// Report describes a feed as the importer sees it, without storing it.
+type Report struct {
+	Url string
+	// Valid is set when no errors were found. Warnings don't stop a feed
+	// from being imported.
+	Valid     bool
+	Format    string
+	Encoding  string
+	Channels  []Channel
+	ItemCount int
+	Issues    []Issue
+}
+
+type ParserInterface interface {
+	Parse(bs []byte) (model.Rss, error)
+}
+
+// Validate parses the document fetched from feedUrl the way an import does
+// and reports what it finds wrong with it.
+func Validate(feedUrl string, bs []byte, p ParserInterface) Report {
+	r := Report{Url: feedUrl}
+
+	r.validate(bs, p)
+
+	r.Valid = !slices.ContainsFunc(r.Issues, func(issue Issue) bool {
+		return issue.Severity == SeverityError
+	})
+
+	return r
+}
+
+func (r *Report) validate(bs []byte, p ParserInterface) {
+	if discovery.IsHtml(bs) {
+		r.Format = "HTML"
+		r.addError("/", htmlMessage(bs, r.Url))
+
+		return
+	}
+
+	r.Format = parser.FormatName(bs)
+
+	r.Encoding = decoder.DeclaredCharset(bs)
+	if r.Encoding == "" {
+		r.Encoding = "UTF-8"
+	}
+
+	rss, err := p.Parse(bs)
+	if err != nil {
+		r.addError("/", err.Error())
+
+		return
+	}
+
+	// The parser reports what it drops or repairs. Unreadable dates are left
+	// to checkDates, which finds the elements they are in.
+	for _, warning := range rss.Warnings {
+		if !strings.Contains(warning, model.ErrInvalidDateTime.Error()) {
+			r.addWarning("/", warning)
+		}
+	}
+
+	r.checkChannels(rss.Channels, pathsFor(r.Format))
+	r.checkDates(bs)
+}
+
+func htmlMessage(bs []byte, pageUrl string) string {
+	const message = "the document is an HTML page, not a feed"
+
+	feedUrls, err := discovery.FeedUrls(bs, pageUrl)
+	if err != nil || len(feedUrls) == 0 {
+		return message
+	}
+
+	return fmt.Sprintf("%v; it may link to %v", message, strings.Join(feedUrls, ", "))
+}
+
+func (r *Report) checkChannels(channels []model.Channel, ps paths) {
+	if len(channels) == 0 {
+		r.addError("/", "the feed has no channel")
+
+		return
+	}
+
+	// GUIDs map to the path of the item that used them first.
+	guids := make(map[string]string)
+
+	for i := range channels {
+		channel := &channels[i]
+		channelPath := ps.channel(i)
+
+		r.Channels = append(r.Channels, Channel{
+			Title:       strings.TrimSpace(channel.Title),
+			Link:        channel.Link,
+			Description: strings.TrimSpace(channel.Description),
+			Language:    strings.TrimSpace(channel.Language),
+			Generator:   channel.Generator,
+			ImageUrl:    channel.ImageUrl,
+			ItemCount:   len(channel.Items),
+		})
+		r.ItemCount += len(channel.Items)
+
+		if strings.TrimSpace(channel.Title) == "" {
+			r.addError(channelPath+"/title", "the channel has no title")
+		}
+
+		r.checkUrl(channelPath+"/"+ps.channelLink, channel.Link)
+		r.checkUrl(channelPath+"/"+ps.image, channel.ImageUrl)
+
+		if len(channel.Items) == 0 {
+			r.addWarning(channelPath+"/"+ps.itemName, "the channel has no items")
+		}
+
+		for j := range channel.Items {
+			r.checkItem(&channel.Items[j], ps.item(i, j), ps, guids)
+		}
+	}
+}
+
+func (r *Report) checkItem(item *model.Item, itemPath string, ps paths, guids map[string]string) {
+	hasTitle := strings.TrimSpace(item.Title) != ""
+	hasText := strings.TrimSpace(item.Description) != "" || strings.TrimSpace(item.Content) != ""
+
+	switch {
+	case !hasTitle && !hasText:
+		r.addError(itemPath, "the item has neither a title nor a description")
+	case !hasTitle:
+		r.addWarning(itemPath+"/title", "the item has no title")
+	}
+
+	r.checkUrl(itemPath+"/"+ps.link, item.Link)
+
+	for _, enclosure := range item.Enclosures {
+		r.checkUrl(itemPath+"/"+ps.enclosure, enclosure.Url)
+	}
+
+	for _, attachment := range item.Attachments {
+		r.checkUrl(itemPath+"/media:content/@url", attachment.Url)
+	}
+
+	if item.Guid.Value == "" {
+		return
+	}
+
+	guidPath := itemPath + "/" + ps.guid
+
+	if first, ok := guids[item.Guid.Value]; ok {
+		r.addWarning(guidPath, fmt.Sprintf(
+			"duplicate GUID %q, already used by %v; only one of the items is kept", item.Guid.Value, first))
+
+		return
+	}
+
+	guids[item.Guid.Value] = itemPath
+}
+
+// checkUrl reports URLs that can't be opened from a browser. Relative URLs
+// are fine, they are resolved on import.
+func (r *Report) checkUrl(path, rawUrl string) {
+	rawUrl = strings.TrimSpace(rawUrl)
+	if rawUrl == "" {
+		return
+	}
+
+	u, err := url.Parse(rawUrl)
+
+	switch {
+	case err != nil:
+		r.addWarning(path, fmt.Sprintf("invalid URL %q", rawUrl))
+	case u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https":
+		r.addWarning(path, fmt.Sprintf("URL %q is not an http(s) URL", rawUrl))
+	case u.Scheme != "" && u.Host == "":
+		r.addWarning(path, fmt.Sprintf("URL %q has no host", rawUrl))
+	}
+}
+
+func (r *Report) addError(path, message string) {
+	r.Issues = append(r.Issues, Issue{Severity: SeverityError, Path: path, Message: message})
+}
+
+func (r *Report) addWarning(path, message string) {
+	r.Issues = append(r.Issues, Issue{Severity: SeverityWarning, Path: path, Message: message})
+}
@@ -0,0 +1,178 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/marchuknikolay/rss-parser/internal/parser"
+)
+
+const feedUrl = "https://example.com/feed.xml"
+
+func TestValidate(t *testing.T) {
+	t.Run("ValidRss", func(t *testing.T) {
+		bs := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<rss version="2.0">
+				<channel>
+					<title>Channel 1</title>
+					<link>https://example.com/</link>
+					<language>en</language>
+					<item>
+						<title>Item 1</title>
+						<link>/items/1</link>
+						<guid>item-1</guid>
+						<pubDate>Sun, 27 Jul 2025 13:45:00 +0300</pubDate>
+					</item>
+				</channel>
+			</rss>`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.True(t, report.Valid)
+		require.Empty(t, report.Issues)
+		require.Equal(t, feedUrl, report.Url)
+		require.Equal(t, parser.FormatRss, report.Format)
+		require.Equal(t, "UTF-8", report.Encoding)
+		require.Equal(t, 1, report.ItemCount)
+		require.Equal(t, []Channel{{
+			Title:     "Channel 1",
+			Link:      "https://example.com/",
+			Language:  "en",
+			ItemCount: 1,
+		}}, report.Channels)
+	})
+
+	t.Run("RssIssues", func(t *testing.T) {
+		bs := []byte(`<rss>
+				<channel>
+					<link>javascript:alert(1)</link>
+					<item>
+						<description>Item 1 description</description>
+						<guid>item-1</guid>
+						<pubDate>yesterday</pubDate>
+					</item>
+					<item>
+						<title>Item 2</title>
+						<link>http://exa mple.com/</link>
+						<guid>item-1</guid>
+					</item>
+					<item></item>
+				</channel>
+			</rss>`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.False(t, report.Valid)
+		require.Equal(t, 3, report.ItemCount)
+		require.Equal(t, []Issue{
+			{SeverityError, "/rss/channel/title", "the channel has no title"},
+			{SeverityWarning, "/rss/channel/link", `URL "javascript:alert(1)" is not an http(s) URL`},
+			{SeverityWarning, "/rss/channel/item[1]/title", "the item has no title"},
+			{SeverityWarning, "/rss/channel/item[2]/link", `invalid URL "http://exa mple.com/"`},
+			{
+				SeverityWarning,
+				"/rss/channel/item[2]/guid",
+				`duplicate GUID "item-1", already used by /rss/channel/item[1]; only one of the items is kept`,
+			},
+			{SeverityError, "/rss/channel/item[3]", "the item has neither a title nor a description"},
+			{
+				SeverityWarning,
+				"/rss/channel/item[1]/pubDate",
+				`unrecognized date format: "yesterday", the date is ignored`,
+			},
+		}, report.Issues)
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		bs := []byte(`<feed xmlns="http://www.w3.org/2005/Atom">
+				<title>Feed 1</title>
+				<entry>
+					<title>Entry 1</title>
+					<updated>2025-07-27T13:45:00+03:00</updated>
+				</entry>
+				<entry>
+					<title>Entry 2</title>
+					<updated>not a date</updated>
+				</entry>
+			</feed>`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.True(t, report.Valid)
+		require.Equal(t, parser.FormatAtom, report.Format)
+		require.Equal(t, []Issue{{
+			SeverityWarning,
+			"/feed/entry[2]/updated",
+			`unrecognized date format: "not a date", the date is ignored`,
+		}}, report.Issues)
+	})
+
+	t.Run("JsonFeed", func(t *testing.T) {
+		bs := []byte(`{
+				"version": "https://jsonfeed.org/version/1.1",
+				"title": "Feed 1",
+				"items": [
+					{"id": "1", "content_text": "Text 1", "date_published": "2025-07-27T13:45:00+03:00"},
+					{"id": "1", "title": "Item 2", "date_published": "soon"}
+				]
+			}`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.Equal(t, parser.FormatJson, report.Format)
+		require.Equal(t, "UTF-8", report.Encoding)
+		require.Equal(t, []Issue{
+			{SeverityWarning, "/items[1]/title", "the item has no title"},
+			{
+				SeverityWarning,
+				"/items[2]/id",
+				`duplicate GUID "1", already used by /items[1]; only one of the items is kept`,
+			},
+			{SeverityWarning, "/items[2]/date_published", `unrecognized date format: "soon", the date is ignored`},
+		}, report.Issues)
+	})
+
+	t.Run("LegacyEncoding", func(t *testing.T) {
+		bs := []byte(`<?xml version="1.0" encoding="windows-1251"?>
+			<rss><channel><title>Channel 1</title><item><title>Item 1</title></item></channel></rss>`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.True(t, report.Valid)
+		require.Equal(t, "windows-1251", report.Encoding)
+	})
+
+	t.Run("Repaired", func(t *testing.T) {
+		bs := []byte(`<rss><channel><title>Tom & Jerry</title><item><title>Item 1</title></item></channel></rss>`)
+
+		report := Validate(feedUrl, bs, parser.Parser{})
+
+		require.True(t, report.Valid)
+		require.NotEmpty(t, report.Issues)
+		require.Equal(t, SeverityWarning, report.Issues[0].Severity)
+		require.Equal(t, "/", report.Issues[0].Path)
+	})
+
+	t.Run("Unparseable", func(t *testing.T) {
+		report := Validate(feedUrl, []byte(`<rss><channel>`), parser.Parser{})
+
+		require.False(t, report.Valid)
+		require.Len(t, report.Issues, 1)
+		require.Equal(t, "/", report.Issues[0].Path)
+		require.Empty(t, report.Channels)
+	})
+
+	t.Run("HtmlPage", func(t *testing.T) {
+		bs := []byte(`<!DOCTYPE html><html><head>
+				<link rel="alternate" type="application/rss+xml" href="/rss.xml">
+			</head><body></body></html>`)
+
+		report := Validate("https://example.com/", bs, parser.Parser{})
+
+		require.False(t, report.Valid)
+		require.Equal(t, "HTML", report.Format)
+		require.Len(t, report.Issues, 1)
+		require.Contains(t, report.Issues[0].Message, "https://example.com/rss.xml")
+	})
+}
@@ -48,6 +48,8 @@ function handlePutItem(event) {
         id: document.getElementById('putItemId').value.trim(),
         title: document.getElementById('putItemTitle').value,
         description: document.getElementById('putItemDescription').value,
+        link: document.getElementById('putItemLink').value,
+        author: document.getElementById('putItemAuthor').value,
         pub_date: document.getElementById('putItemPubDate').value,
     };
 